@@ -0,0 +1,175 @@
+package models
+
+import "time"
+
+// AlertQuery 告警查询条件
+type AlertQuery struct {
+	Limit  int        `json:"limit"`
+	Level  string     `json:"level,omitempty"`
+	RuleID int64      `json:"rule_id,omitempty"`
+	Status string     `json:"status,omitempty"`
+	Since  *time.Time `json:"since,omitempty"`
+	Until  *time.Time `json:"until,omitempty"`
+}
+
+// AlertSilence 告警静默，在有效期内抑制匹配告警的通知
+type AlertSilence struct {
+	ID        int64     `json:"id"`
+	RuleID    int64     `json:"rule_id"`
+	Target    string    `json:"target,omitempty"`   // 静默的目标，为空时静默规则的所有目标
+	AlertID   int64     `json:"alert_id,omitempty"` // 创建静默时对应的告警
+	CreatedBy string    `json:"created_by"`
+	Comment   string    `json:"comment"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Expired   bool      `json:"expired"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Active 检查静默在指定时间是否生效
+func (s AlertSilence) Active(now time.Time) bool {
+	return !s.Expired && !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// Matches 检查静默是否作用于规则的指定目标
+func (s AlertSilence) Matches(ruleID int64, target string) bool {
+	return s.RuleID == ruleID && (s.Target == "" || s.Target == target)
+}
+
+// MaintenanceWindow 按计划重复的维护窗口，窗口内触发的告警照常记录但不发送通知
+type MaintenanceWindow struct {
+	ID        int64         `json:"id"`
+	Name      string        `json:"name"`
+	Schedule  string        `json:"schedule"` // 窗口开始时间的 cron 表达式（分 时 日 月 周），如 "0 2 * * *" 表示每天 2:00
+	Duration  time.Duration `json:"duration"` // 每次维护持续的时长
+	Timezone  string        `json:"timezone"` // 解释 cron 表达式使用的时区，如 Asia/Shanghai，为空时使用本地时区
+	RuleIDs   []int64       `json:"rule_ids"` // 受影响的规则，为空时作用于所有规则
+	Enabled   bool          `json:"enabled"`
+	Comment   string        `json:"comment"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// AnomalyConfig 异常检测规则配置，基线从历史数据中学习
+type AnomalyConfig struct {
+	Sigma      float64       `json:"sigma" yaml:"sigma,omitempty"`             // 偏离基线的标准差倍数
+	Window     time.Duration `json:"window" yaml:"window,omitempty"`           // 学习基线的历史时长
+	Seasonal   bool          `json:"seasonal" yaml:"seasonal,omitempty"`       // 按一天中的小时分别学习基线
+	MinSamples int           `json:"min_samples" yaml:"min_samples,omitempty"` // 基线所需的最少样本数
+}
+
+// ProcessConfig 进程规则配置，在完整进程表中按名称、命令行和用户匹配进程，多个条件须同时满足
+type ProcessConfig struct {
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`       // 进程名称，支持通配符，不区分大小写
+	Cmdline   string `json:"cmdline,omitempty" yaml:"cmdline,omitempty"` // 命令行正则表达式
+	User      string `json:"user,omitempty" yaml:"user,omitempty"`       // 进程所属用户，支持通配符
+	Condition string `json:"condition" yaml:"condition,omitempty"`       // absent、present、count、cpu 或 rss（MB），后三者使用规则的 Operator/Threshold
+}
+
+// FlapConfig 告警抖动检测配置，窗口内状态变化次数达到阈值时视为抖动
+type FlapConfig struct {
+	Changes int           `json:"changes" yaml:"changes,omitempty"` // 触发抖动的状态变化次数
+	Window  time.Duration `json:"window" yaml:"window,omitempty"`   // 统计状态变化的时间窗口，窗口内无状态变化时视为恢复稳定
+}
+
+// EscalationPolicy 告警升级策略，告警持续未被确认时重复通知或升级
+type EscalationPolicy struct {
+	RepeatInterval time.Duration    `json:"repeat_interval" yaml:"repeat_interval,omitempty"` // 重复通知的间隔，为0时不重复
+	Steps          []EscalationStep `json:"steps" yaml:"steps,omitempty"`                     // 按 After 升序排列的升级步骤
+}
+
+// EscalationStep 告警触发后持续 After 仍未被确认时执行的升级
+type EscalationStep struct {
+	After   time.Duration `json:"after" yaml:"after,omitempty"`
+	Level   string        `json:"level,omitempty" yaml:"level,omitempty"`     // 升级后的告警级别，为空时不变
+	Actions []AlertAction `json:"actions,omitempty" yaml:"actions,omitempty"` // 升级后改用的告警动作，为空时沿用之前的动作
+}
+
+// RulePack 导出的告警规则集合，可纳入版本管理并导入到其他机器
+type RulePack struct {
+	Version    int         `json:"version" yaml:"version"`
+	ExportedAt time.Time   `json:"exported_at" yaml:"exported_at"`
+	Rules      []AlertRule `json:"rules" yaml:"rules"`
+}
+
+// RuleChange 导入时规则的变更，Fields 为发生变化的字段
+type RuleChange struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+}
+
+// RuleImportResult 规则导入结果，DryRun 为 true 时仅为预览，未实际修改规则
+type RuleImportResult struct {
+	Created   []string     `json:"created"`
+	Updated   []RuleChange `json:"updated"`
+	Deleted   []string     `json:"deleted"`
+	Unchanged []string     `json:"unchanged"`
+	DryRun    bool         `json:"dry_run"`
+}
+
+// BacktestFiring 回测中规则的一次触发
+type BacktestFiring struct {
+	Target   string        `json:"target,omitempty"`
+	StartsAt time.Time     `json:"starts_at"`
+	EndsAt   *time.Time    `json:"ends_at,omitempty"` // 回测结束时仍在触发则为空
+	Duration time.Duration `json:"duration"`
+	Value    float64       `json:"value"` // 触发时的取值
+}
+
+// BacktestResult 告警规则在历史数据上的回测结果
+type BacktestResult struct {
+	Since      time.Time        `json:"since"`
+	Until      time.Time        `json:"until"`
+	Samples    int              `json:"samples"` // 回放的采集次数
+	Firings    []BacktestFiring `json:"firings"`
+	FiringTime time.Duration    `json:"firing_time"` // 所有触发的累计时长
+}
+
+// Notification 发送到外部通知渠道的告警通知
+type Notification struct {
+	Event     string    `json:"event"` // firing 或 resolved
+	Alert     Alert     `json:"alert"`
+	Alerts    []Alert   `json:"alerts,omitempty"` // 分组通知包含的所有告警，Alert 为其中第一个
+	Rule      AlertRule `json:"rule"`
+	Host      string    `json:"host"`
+	Message   string    `json:"message"`  // 按告警动作的模板渲染的消息
+	Language  string    `json:"language"` // 通知使用的语言，如 zh-CN、en-US
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AlertGroup 一起发送通知的一组告警
+type AlertGroup struct {
+	Event  string            `json:"event"`  // firing 或 resolved
+	Labels map[string]string `json:"labels"` // 分组依据的标签
+	Alerts []Alert           `json:"alerts"`
+}
+
+// RemediationExecution 修复动作执行记录，向多个进程发送信号时每个进程一条记录
+type RemediationExecution struct {
+	ID        int64         `json:"id"`
+	AlertID   int64         `json:"alert_id"`
+	RuleID    int64         `json:"rule_id"`
+	Action    string        `json:"action"`           // command 或 signal
+	Target    string        `json:"target"`           // 命令名称或信号名称
+	PID       int32         `json:"pid,omitempty"`    // 接收信号的进程
+	Status    string        `json:"status"`           // succeeded、failed 或 rejected（不在白名单中）
+	ExitCode  int           `json:"exit_code"`        // 命令的退出码，未能运行或超时为 -1
+	Output    string        `json:"output,omitempty"` // 命令的标准输出和标准错误，过长时截断
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// NotificationDelivery 通知投递记录
+type NotificationDelivery struct {
+	ID        int64     `json:"id"`
+	AlertID   int64     `json:"alert_id"`
+	RuleID    int64     `json:"rule_id"`
+	Channel   string    `json:"channel"`
+	Target    string    `json:"target"`
+	Event     string    `json:"event"`
+	Status    string    `json:"status"` // delivered 或 failed
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -155,6 +156,68 @@ func NewDiskIOStats() ([]DiskIOStats, error) {
 	return diskIOStats, nil
 }
 
+// DiskIORate 磁盘I/O速率，由相邻两次采样的差值计算
+type DiskIORate struct {
+	Mountpoint string    `json:"mountpoint"`
+	Device     string    `json:"device"`
+	ReadRate   float64   `json:"read_rate"`  // 读取速率 (字节/秒)
+	WriteRate  float64   `json:"write_rate"` // 写入速率 (字节/秒)
+	IOPS       float64   `json:"iops"`       // 每秒读写次数
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// CalculateDiskIORate 计算磁盘读写速率和IOPS
+func CalculateDiskIORate(current, previous *DiskInfo, duration time.Duration) DiskIORate {
+	rate := DiskIORate{
+		Mountpoint: current.Mountpoint,
+		Device:     current.Device,
+		Timestamp:  current.Timestamp,
+	}
+	if current.IOStats == nil || previous == nil || previous.IOStats == nil || duration.Seconds() == 0 {
+		return rate
+	}
+
+	cur, prev := current.IOStats, previous.IOStats
+	rate.ReadRate = counterRate(cur.ReadBytes, prev.ReadBytes, duration)
+	rate.WriteRate = counterRate(cur.WriteBytes, prev.WriteBytes, duration)
+	rate.IOPS = counterRate(cur.ReadCount+cur.WriteCount, prev.ReadCount+prev.WriteCount, duration)
+
+	return rate
+}
+
+// DiskForecast 磁盘空间预测，根据历史使用量的线性趋势估算写满时间
+type DiskForecast struct {
+	Mountpoint      string     `json:"mountpoint"`
+	Device          string     `json:"device"`
+	Used            uint64     `json:"used"`
+	Free            uint64     `json:"free"`
+	UsedPercent     float64    `json:"used_percent"`
+	FillRate        float64    `json:"fill_rate"`                   // 使用量增长速率 (字节/小时)，负数表示在释放空间
+	HoursToFull     *float64   `json:"hours_to_full,omitempty"`     // 预计写满所需小时数，使用量不增长时为空
+	PredictedFullAt *time.Time `json:"predicted_full_at,omitempty"` // 预计写满的时间
+	Samples         int        `json:"samples"`
+	Window          float64    `json:"window"` // 拟合使用的历史时长 (小时)
+	Timestamp       time.Time  `json:"timestamp"`
+}
+
+// AttachDiskIOStats 按设备名将I/O统计关联到磁盘分区
+func AttachDiskIOStats(diskInfos []DiskInfo, ioStats []DiskIOStats) {
+	statsMap := make(map[string]*DiskIOStats, len(ioStats))
+	for i := range ioStats {
+		statsMap[ioStats[i].Name] = &ioStats[i]
+	}
+
+	for i := range diskInfos {
+		// Linux 下设备为 /dev/sda1，I/O统计名称为 sda1
+		name := filepath.Base(diskInfos[i].Device)
+		if stats, exists := statsMap[name]; exists {
+			diskInfos[i].IOStats = stats
+		} else if stats, exists := statsMap[diskInfos[i].Device]; exists {
+			diskInfos[i].IOStats = stats
+		}
+	}
+}
+
 // GetDiskUsageSummary 获取磁盘使用摘要
 func GetDiskUsageSummary(diskInfos []DiskInfo) *DiskUsageSummary {
 	summary := &DiskUsageSummary{
@@ -148,6 +148,41 @@ func CalculateNetworkRate(current, previous *NetworkInfo, duration time.Duration
 	return uploadRate, downloadRate
 }
 
+// NetworkRate 网络接口速率，由相邻两次采样的差值计算
+type NetworkRate struct {
+	Interface  string    `json:"interface"`
+	RxRate     float64   `json:"rx_rate"`     // 接收速率 (字节/秒)
+	TxRate     float64   `json:"tx_rate"`     // 发送速率 (字节/秒)
+	ErrorsRate float64   `json:"errors_rate"` // 收发错误 (个/秒)
+	DropsRate  float64   `json:"drops_rate"`  // 收发丢包 (个/秒)
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// CalculateNetworkRates 计算网络接口的流量、错误和丢包速率
+func CalculateNetworkRates(current, previous *NetworkInfo, duration time.Duration) NetworkRate {
+	rate := NetworkRate{
+		Interface: current.Name,
+		Timestamp: current.Timestamp,
+	}
+	if previous == nil || duration.Seconds() == 0 {
+		return rate
+	}
+
+	rate.TxRate, rate.RxRate = CalculateNetworkRate(current, previous, duration)
+	rate.ErrorsRate = counterRate(current.Errin+current.Errout, previous.Errin+previous.Errout, duration)
+	rate.DropsRate = counterRate(current.Dropin+current.Dropout, previous.Dropin+previous.Dropout, duration)
+
+	return rate
+}
+
+// counterRate 计算累计计数器的每秒增量，计数器重置时返回0
+func counterRate(current, previous uint64, duration time.Duration) float64 {
+	if current < previous || duration.Seconds() == 0 {
+		return 0
+	}
+	return float64(current-previous) / duration.Seconds()
+}
+
 // GetNetworkSummary 获取网络摘要信息
 func GetNetworkSummary() (map[string]interface{}, error) {
 	interfaces, err := NewNetworkInfo()
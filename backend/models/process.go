@@ -24,37 +24,65 @@ type ProcessTimes struct {
 
 // AlertRule 告警规则
 type AlertRule struct {
-	ID          int64           `json:"id"`
-	Name        string          `json:"name"`
-	Metric      string          `json:"metric"`
-	Operator    string          `json:"operator"`
-	Threshold   float64         `json:"threshold"`
-	Duration    time.Duration   `json:"duration"`
-	Enabled     bool            `json:"enabled"`
-	Actions     []AlertAction   `json:"actions"`
-	CreatedAt   time.Time       `json:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at"`
+	ID              int64             `json:"id" yaml:"-"`
+	Name            string            `json:"name" yaml:"name,omitempty"`
+	Metric          string            `json:"metric" yaml:"metric,omitempty"`
+	Target          string            `json:"target" yaml:"target,omitempty"` // 目标选择器：挂载点、设备、网络接口名或通配符，any/all 表示聚合所有目标
+	Operator        string            `json:"operator" yaml:"operator,omitempty"`
+	Threshold       float64           `json:"threshold" yaml:"threshold,omitempty"`
+	ClearThreshold  *float64          `json:"clear_threshold,omitempty" yaml:"clear_threshold,omitempty"`   // 恢复阈值，设置后活动告警需越过该值才解决，避免在阈值附近反复触发
+	Expression      string            `json:"expression,omitempty" yaml:"expression,omitempty"`             // 复合条件表达式，设置后替代 Metric/Operator/Threshold
+	Type            string            `json:"type,omitempty" yaml:"type,omitempty"`                         // 规则类型：threshold（默认）、anomaly 或 process
+	Anomaly         *AnomalyConfig    `json:"anomaly,omitempty" yaml:"anomaly,omitempty"`                   // 异常检测配置，Type 为 anomaly 时使用
+	Process         *ProcessConfig    `json:"process,omitempty" yaml:"process,omitempty"`                   // 进程匹配条件，Type 为 process 时使用
+	Flap            *FlapConfig       `json:"flap,omitempty" yaml:"flap,omitempty"`                         // 抖动检测配置，为空时不检测
+	Escalation      *EscalationPolicy `json:"escalation,omitempty" yaml:"escalation,omitempty"`             // 升级策略，为空时只在触发时通知一次
+	Inhibits        []string          `json:"inhibits,omitempty" yaml:"inhibits,omitempty"`                 // 本规则的活动告警抑制的规则名称，只抑制级别更低的告警
+	MessageTemplate string            `json:"message_template,omitempty" yaml:"message_template,omitempty"` // 告警消息模板（Go text/template），为空时使用界面语言的内置模板
+	Duration        time.Duration     `json:"duration" yaml:"duration,omitempty"`
+	Enabled         bool              `json:"enabled" yaml:"enabled,omitempty"`
+	Actions         []AlertAction     `json:"actions" yaml:"actions,omitempty"`
+	CreatedAt       time.Time         `json:"created_at" yaml:"-"`
+	UpdatedAt       time.Time         `json:"updated_at" yaml:"-"`
 }
 
 // AlertAction 告警动作
 type AlertAction struct {
-	Type   string `json:"type"`
-	Target string `json:"target"`
-	Level  string `json:"level"`
+	Type     string `json:"type" yaml:"type,omitempty"`
+	Target   string `json:"target" yaml:"target,omitempty"`
+	Level    string `json:"level" yaml:"level,omitempty"`
+	Template string `json:"template,omitempty" yaml:"template,omitempty"` // 该渠道使用的消息模板（Go text/template），为空时使用告警消息
 }
 
 // Alert 告警
 type Alert struct {
-	ID         int64      `json:"id"`
-	RuleID     int64      `json:"rule_id"`
-	RuleName   string     `json:"rule_name"`
-	Message    string     `json:"message"`
-	Level      string     `json:"level"`
-	Value      float64    `json:"value"`
-	Threshold  float64    `json:"threshold"`
-	Status     string     `json:"status"`
-	CreatedAt  time.Time  `json:"created_at"`
-	ResolvedAt *time.Time `json:"resolved_at"`
+	ID           int64      `json:"id"`
+	RuleID       int64      `json:"rule_id"`
+	RuleName     string     `json:"rule_name"`
+	Target       string     `json:"target,omitempty"` // 告警对应的挂载点或网络接口
+	Message      string     `json:"message"`
+	Level        string     `json:"level"`
+	Value        float64    `json:"value"`
+	Threshold    float64    `json:"threshold"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ResolvedAt   *time.Time `json:"resolved_at"`
+	PendingSince *time.Time `json:"pending_since,omitempty"` // 条件首次满足的时间
+
+	// 确认信息，确认后不再重复通知但告警仍保持活动
+	AcknowledgedBy string     `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AckComment     string     `json:"ack_comment,omitempty"`
+
+	Silenced   bool `json:"silenced"`   // 是否被静默
+	Flapping   bool `json:"flapping"`   // 是否处于抖动状态，抖动期间不发送通知
+	Suppressed bool `json:"suppressed"` // 是否在维护窗口内触发，维护期间不发送通知
+	Inhibited  bool `json:"inhibited"`  // 是否被其他规则级别更高的活动告警抑制
+	Remediated bool `json:"remediated"` // 是否已执行修复动作，每次触发只执行一次
+
+	// 升级状态
+	Escalation     int        `json:"escalation,omitempty"`       // 已执行的升级步骤数
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty"` // 最近一次发送通知的时间
 }
 
 // ProcessInfo 进程信息
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// 告警规则类型
+const (
+	ruleTypeThreshold = "threshold"
+	ruleTypeAnomaly   = "anomaly"
+)
+
+// 异常检测的默认配置
+const (
+	defaultAnomalySigma      = 3.0
+	defaultAnomalyWindow     = 7 * 24 * time.Hour
+	defaultAnomalyMinSamples = 60
+)
+
+// anomalyBaselineTTL 基线缓存时长，避免每次评估都扫描历史数据
+const anomalyBaselineTTL = 15 * time.Minute
+
+// anomalyValueWindow 与基线比较的当前值取最近1分钟原始数据的均值，与基线使用的1分钟汇总粒度一致
+const anomalyValueWindow = time.Minute
+
+// anomalyMetricAliases 异常检测规则中指标名称的简写
+var anomalyMetricAliases = map[string]string{
+	"cpu":    "cpu.usage",
+	"memory": "memory.used_percent",
+	"disk":   "disk.used_percent",
+}
+
+// anomalyBaseline 从历史数据学习的基线
+type anomalyBaseline struct {
+	Mean       float64
+	StdDev     float64
+	Samples    int
+	ComputedAt time.Time
+}
+
+// anomalyConfig 返回补齐默认值后的异常检测配置
+func anomalyConfig(rule models.AlertRule) models.AnomalyConfig {
+	var cfg models.AnomalyConfig
+	if rule.Anomaly != nil {
+		cfg = *rule.Anomaly
+	}
+	if cfg.Sigma <= 0 {
+		cfg.Sigma = defaultAnomalySigma
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultAnomalyWindow
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = defaultAnomalyMinSamples
+	}
+	return cfg
+}
+
+// anomalyMetric 解析异常检测规则的指标名称
+func anomalyMetric(metric string) string {
+	if alias, ok := anomalyMetricAliases[metric]; ok {
+		return alias
+	}
+	return metric
+}
+
+// validateAnomalyRule 验证异常检测规则
+func validateAnomalyRule(rule models.AlertRule) error {
+	if rule.Expression != "" {
+		return fmt.Errorf("anomaly rules cannot use an expression")
+	}
+
+	name := anomalyMetric(rule.Metric)
+	if _, _, ok := findHistoryField(name); !ok {
+		return fmt.Errorf("metric %s has no history for anomaly detection", rule.Metric)
+	}
+	if _, ok := exprFields[name]; !ok {
+		return fmt.Errorf("unknown metric: %s", rule.Metric)
+	}
+
+	if rule.Anomaly != nil {
+		if rule.Anomaly.Sigma < 0 {
+			return fmt.Errorf("sigma cannot be negative")
+		}
+		if rule.Anomaly.Window < 0 {
+			return fmt.Errorf("baseline window cannot be negative")
+		}
+	}
+
+	return nil
+}
+
+// evaluateAnomaly 将各目标最近1分钟的均值与历史基线比较，偏离超过设定的标准差倍数时触发
+func (e *ruleEvaluator) evaluateAnomaly(rule models.AlertRule, data map[string]interface{}) ([]alertInstance, error) {
+	if e.storage == nil {
+		return nil, fmt.Errorf("anomaly detection requires history storage")
+	}
+
+	cfg := anomalyConfig(rule)
+	name := anomalyMetric(rule.Metric)
+	src, field, _ := findHistoryField(name)
+
+	samples, err := exprFields[name](data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 基线是1分钟均值的分布，单个采样的波动远大于1分钟均值，直接比较会误报
+	recent, err := e.storage.metricWindow(src, field, e.now, anomalyValueWindow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent values: %w", err)
+	}
+
+	instances := make([]alertInstance, 0, len(samples))
+	for _, sample := range samples {
+		if sample.Target != "" && !targetSelected(rule.Target, sample.Names) {
+			continue
+		}
+
+		baseline, err := e.anomalyBaseline(rule, cfg, src, field, sample.Target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to learn baseline: %w", err)
+		}
+
+		// 没有历史数据时（如存储写入失败）使用当前采样
+		value := sample.Value
+		if st, ok := recent[sample.Target]; ok && st.samples > 0 {
+			value = st.sum / float64(st.samples)
+		}
+		instance := alertInstance{Target: sample.Target, Value: value}
+
+		// 样本不足或基线没有波动时不判断异常
+		if baseline.Samples >= cfg.MinSamples && baseline.StdDev > 0 {
+			deviation := (value - baseline.Mean) / baseline.StdDev
+
+			switch rule.Operator {
+			case ">", ">=":
+				instance.Triggered = deviation >= cfg.Sigma
+			case "<", "<=":
+				instance.Triggered = -deviation >= cfg.Sigma
+			default:
+				instance.Triggered = math.Abs(deviation) >= cfg.Sigma
+			}
+
+			instance.Message = e.formatAnomalyMessage(rule, sample.Target, value, baseline, deviation)
+		}
+
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+// anomalyBaseline 获取目标的基线，按配置区分一天中的小时，缓存过期后重新计算。
+// 缓存按规则的更新时间区分，评估期间规则被修改时不会写入旧配置计算的基线
+func (e *ruleEvaluator) anomalyBaseline(rule models.AlertRule, cfg models.AnomalyConfig, src rollupSource, field rollupField, target string) (anomalyBaseline, error) {
+	now := e.now
+	hour := -1
+	if cfg.Seasonal {
+		hour = now.Hour()
+	}
+
+	key := fmt.Sprintf("%s/%d/%d", alertKey(rule.ID, target), rule.UpdatedAt.UnixNano(), hour)
+	e.as.cacheMu.Lock()
+	baseline, ok := e.as.baselines[key]
+	e.as.cacheMu.Unlock()
+	if ok && now.Sub(baseline.ComputedAt) < anomalyBaselineTTL {
+		return baseline, nil
+	}
+
+	// 不使用最近一个持续时间内的数据，避免正在发生的异常拉高基线
+	until := now.Add(-rule.Duration)
+	mean, stddev, samples, err := e.storage.metricBaseline(src, field, target, now.Add(-cfg.Window), until, hour)
+	if err != nil {
+		return anomalyBaseline{}, err
+	}
+
+	baseline = anomalyBaseline{Mean: mean, StdDev: stddev, Samples: samples, ComputedAt: now}
+	e.as.cacheMu.Lock()
+	e.as.baselines[key] = baseline
+	e.as.cacheMu.Unlock()
+	return baseline, nil
+}
+
+// formatAnomalyMessage 格式化异常检测告警消息
+func (e *ruleEvaluator) formatAnomalyMessage(rule models.AlertRule, target string, value float64, baseline anomalyBaseline, deviation float64) string {
+	data := newMessageData(rule, e.host, target, value, e.language)
+	data.Threshold = anomalyConfig(rule).Sigma
+	data.Mean = baseline.Mean
+	data.StdDev = baseline.StdDev
+	data.Deviation = math.Abs(deviation)
+	return renderMessage(rule.MessageTemplate, e.language, templateAnomaly, data)
+}
+
+// metricBaseline 从1分钟汇总数据计算字段在 [since, until) 内的均值和标准差，hour 不小于0时只统计该小时的数据
+func (s *StorageService) metricBaseline(src rollupSource, field rollupField, series string, since, until time.Time, hour int) (float64, float64, int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*), COALESCE(AVG(avg_value), 0), COALESCE(AVG(avg_value * avg_value), 0)
+		FROM %s WHERE metric = ? AND field = ? AND series = ? AND bucket >= ? AND bucket < ?`, rollupTiers[0].Table)
+	args := []interface{}{src.Metric, field.Key, series, since.Unix(), until.Unix()}
+
+	if hour >= 0 {
+		query += " AND CAST(strftime('%H', bucket, 'unixepoch', 'localtime') AS INTEGER) = ?"
+		args = append(args, hour)
+	}
+
+	var samples int
+	var mean, meanSquare float64
+	if err := s.db.QueryRow(query, args...).Scan(&samples, &mean, &meanSquare); err != nil {
+		return 0, 0, 0, err
+	}
+
+	variance := meanSquare - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+
+	return mean, math.Sqrt(variance), samples, nil
+}
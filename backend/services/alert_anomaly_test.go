@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+func TestAnomalyComparesMinuteAverage(t *testing.T) {
+	as, storage := newTestAlertingService(t)
+	now := time.Now()
+
+	// 基线：过去两小时的1分钟均值在 19 和 21 之间交替，均值 20，标准差 1
+	for i := 2; i <= 120; i++ {
+		avg := 19.0
+		if i%2 == 0 {
+			avg = 21
+		}
+		if _, err := storage.db.Exec(`INSERT INTO metric_rollup_1m (bucket, metric, series, field, samples, min_value, avg_value, max_value, p95_value)
+			VALUES (?, 'cpu', '', 'usage', 30, ?, ?, ?, ?)`, now.Add(-time.Duration(i)*time.Minute).Unix(), avg, avg, avg, avg); err != nil {
+			t.Fatalf("failed to insert rollup: %v", err)
+		}
+	}
+
+	insertRecent := func(usage ...float64) {
+		t.Helper()
+		if _, err := storage.db.Exec(`DELETE FROM cpu_history`); err != nil {
+			t.Fatalf("failed to clear history: %v", err)
+		}
+		for i, u := range usage {
+			ts := now.Add(-time.Duration(len(usage)-1-i) * 2 * time.Second).Unix()
+			if _, err := storage.db.Exec(`INSERT INTO cpu_history (timestamp, usage_percent, load1, load5, load15) VALUES (?, ?, 0, 0, 0)`, ts, u); err != nil {
+				t.Fatalf("failed to insert history: %v", err)
+			}
+		}
+	}
+
+	rule := models.AlertRule{ID: 1, Type: ruleTypeAnomaly, Metric: "cpu", Operator: ">"}
+	eval := &ruleEvaluator{as: as, storage: storage, now: now}
+	evaluate := func(current float64) alertInstance {
+		t.Helper()
+		instances, err := eval.evaluateAnomaly(rule, testMetrics(current))
+		if err != nil {
+			t.Fatalf("evaluateAnomaly failed: %v", err)
+		}
+		if len(instances) != 1 {
+			t.Fatalf("expected one instance, got %d", len(instances))
+		}
+		return instances[0]
+	}
+
+	// 单个采样的尖峰被1分钟均值平滑，不视为异常
+	spike := make([]float64, 30)
+	for i := range spike {
+		spike[i] = 20
+	}
+	spike[29] = 60
+	insertRecent(spike...)
+	if instance := evaluate(60); instance.Triggered {
+		t.Errorf("single-sample spike triggered an anomaly, value %v", instance.Value)
+	}
+
+	// 持续一分钟的升高触发
+	for i := range spike {
+		spike[i] = 60
+	}
+	insertRecent(spike...)
+	if instance := evaluate(60); !instance.Triggered || instance.Value != 60 {
+		t.Errorf("sustained increase did not trigger, got %+v", instance)
+	}
+}
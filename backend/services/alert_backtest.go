@@ -0,0 +1,246 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// maxBacktestRange 单次回测的最长时间范围
+const maxBacktestRange = 31 * 24 * time.Hour
+
+// backtestMaxGap 相邻两次采集的最大间隔，超过时视为监控未运行，等待中的告警重新计时
+const backtestMaxGap = 5 * time.Minute
+
+// backtestSource 回测使用的历史数据
+type backtestSource struct {
+	Table     string
+	SeriesCol string // 区分目标的列，为空表示单一序列
+	DeviceCol string // 设备列，可用于匹配目标选择器
+	Value     string // 取值的 SQL 表达式，与实时告警的指标取值一致
+
+	// Counter 为 true 时取值是累计计数器，与实时告警一样按相邻两次采样换算为每秒速率，再除以 Scale
+	Counter bool
+	Scale   float64
+}
+
+// backtestSources 支持回测的指标
+var backtestSources = map[string]backtestSource{
+	"cpu":     {Table: "cpu_history", Value: "usage_percent"},
+	"memory":  {Table: "memory_history", Value: "used_percent"},
+	"disk":    {Table: "disk_history", SeriesCol: "mountpoint", DeviceCol: "device", Value: "used_percent"},
+	"network": {Table: "network_history", SeriesCol: "interface", Value: "bytes_sent + bytes_recv", Counter: true, Scale: bytesPerMB},
+}
+
+// backtestInstance 回测中单个目标的状态
+type backtestInstance struct {
+	pendingSince *time.Time
+	firing       *models.BacktestFiring
+}
+
+// BacktestRule 在历史数据上回放规则，按实时评估相同的持续时间和恢复阈值语义计算规则会在何时触发和解决。
+// 回测不会产生告警或通知，until 为零值时回测到当前时间。
+func (as *AlertingService) BacktestRule(rule models.AlertRule, since, until time.Time) (models.BacktestResult, error) {
+	as.mu.RLock()
+	storage := as.storage
+	err := as.validateRule(rule)
+	as.mu.RUnlock()
+
+	if storage == nil {
+		return models.BacktestResult{}, fmt.Errorf("storage service not available")
+	}
+	if err != nil {
+		return models.BacktestResult{}, fmt.Errorf("invalid rule: %w", err)
+	}
+	if rule.Type == ruleTypeAnomaly || rule.Type == ruleTypeProcess || rule.Expression != "" {
+		return models.BacktestResult{}, fmt.Errorf("backtest only supports threshold rules")
+	}
+
+	src, ok := backtestSources[rule.Metric]
+	if !ok {
+		return models.BacktestResult{}, fmt.Errorf("no history is stored for metric %s", rule.Metric)
+	}
+
+	if until.IsZero() {
+		until = as.now()
+	}
+	if !since.Before(until) {
+		return models.BacktestResult{}, fmt.Errorf("backtest start must be before end")
+	}
+	if until.Sub(since) > maxBacktestRange {
+		return models.BacktestResult{}, fmt.Errorf("backtest range cannot exceed %s", maxBacktestRange)
+	}
+
+	result := models.BacktestResult{
+		Since:   since,
+		Until:   until,
+		Firings: make([]models.BacktestFiring, 0),
+	}
+	instances := make(map[string]*backtestInstance)
+
+	end := func(instance *backtestInstance, now time.Time) {
+		firing := instance.firing
+		firing.EndsAt = &now
+		firing.Duration = now.Sub(firing.StartsAt)
+		result.Firings = append(result.Firings, *firing)
+		instance.firing = nil
+	}
+
+	var last time.Time
+	err = storage.replayHistory(src, since, until, func(now time.Time, samples []metricSample) {
+		result.Samples++
+
+		// 监控未运行期间不会积累等待时间，活动告警则会在重启后恢复
+		if !last.IsZero() && now.Sub(last) > backtestMaxGap {
+			for _, instance := range instances {
+				instance.pendingSince = nil
+			}
+		}
+		last = now
+
+		seen := make(map[string]bool)
+		for _, evaluated := range as.evaluateInstances(rule, samples) {
+			seen[evaluated.Target] = true
+			instance, ok := instances[evaluated.Target]
+			if !ok {
+				instance = &backtestInstance{}
+				instances[evaluated.Target] = instance
+			}
+
+			if !evaluated.Triggered {
+				instance.pendingSince = nil
+				if instance.firing != nil && !as.holdsActive(rule, evaluated.Value) {
+					end(instance, now)
+				}
+				continue
+			}
+			if instance.firing != nil {
+				continue
+			}
+
+			if instance.pendingSince == nil {
+				start := now
+				instance.pendingSince = &start
+			}
+			if now.Sub(*instance.pendingSince) < rule.Duration {
+				continue
+			}
+
+			instance.pendingSince = nil
+			instance.firing = &models.BacktestFiring{
+				Target:   evaluated.Target,
+				StartsAt: now,
+				Value:    evaluated.Value,
+			}
+		}
+
+		// 目标消失时解决对应的告警
+		for target, instance := range instances {
+			if seen[target] {
+				continue
+			}
+			instance.pendingSince = nil
+			if instance.firing != nil {
+				end(instance, now)
+			}
+		}
+	})
+	if err != nil {
+		return models.BacktestResult{}, fmt.Errorf("failed to replay history: %w", err)
+	}
+
+	// 回测结束时仍在触发的告警
+	for _, instance := range instances {
+		if instance.firing != nil {
+			instance.firing.Duration = last.Sub(instance.firing.StartsAt)
+			result.Firings = append(result.Firings, *instance.firing)
+		}
+	}
+
+	sort.Slice(result.Firings, func(i, j int) bool {
+		return result.Firings[i].StartsAt.Before(result.Firings[j].StartsAt)
+	})
+	for _, firing := range result.Firings {
+		result.FiringTime += firing.Duration
+	}
+
+	return result, nil
+}
+
+// replayHistory 按时间顺序读取指标的原始历史数据，每个采集时刻调用一次 fn
+func (s *StorageService) replayHistory(src backtestSource, since, until time.Time, fn func(now time.Time, samples []metricSample)) error {
+	seriesCol, deviceCol := "''", "''"
+	if src.SeriesCol != "" {
+		seriesCol = src.SeriesCol
+	}
+	if src.DeviceCol != "" {
+		deviceCol = src.DeviceCol
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf("SELECT timestamp, %s, %s, %s FROM %s WHERE timestamp >= ? AND timestamp <= ? ORDER BY timestamp, id",
+		seriesCol, deviceCol, src.Value, src.Table), since.Unix(), until.Unix())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// 计数器类指标每个序列上一次采样的时间和取值
+	type counterSample struct {
+		timestamp int64
+		value     float64
+	}
+	previous := make(map[string]counterSample)
+
+	var current int64
+	var samples []metricSample
+	flush := func() {
+		if len(samples) > 0 {
+			fn(time.Unix(current, 0), samples)
+		}
+	}
+
+	for rows.Next() {
+		var timestamp int64
+		var series, device string
+		var value float64
+		if err := rows.Scan(&timestamp, &series, &device, &value); err != nil {
+			return err
+		}
+
+		if timestamp != current {
+			flush()
+			current = timestamp
+			samples = nil
+		}
+
+		if src.Counter {
+			// 首次采样、计数器重置或监控中断后的第一次采样没有可用的差值，速率为0
+			last, ok := previous[series]
+			previous[series] = counterSample{timestamp: timestamp, value: value}
+			elapsed := time.Duration(timestamp-last.timestamp) * time.Second
+			if ok && value >= last.value && elapsed > 0 && elapsed <= backtestMaxGap {
+				value = (value - last.value) / elapsed.Seconds() / src.Scale
+			} else {
+				value = 0
+			}
+		}
+
+		sample := metricSample{Target: series, Value: value}
+		if series != "" {
+			sample.Names = []string{series}
+			if device != "" {
+				sample.Names = append(sample.Names, device, filepath.Base(device))
+			}
+		}
+		samples = append(samples, sample)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	flush()
+	return nil
+}
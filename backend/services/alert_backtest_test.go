@@ -0,0 +1,169 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+func TestBacktestNetworkReplaysRate(t *testing.T) {
+	as, storage := newTestAlertingService(t)
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// 累计流量每分钟增加 60 MB 或 600 MB，即 1 MB/s 或 10 MB/s
+	total := int64(1 << 40)
+	for i, increase := range []int64{0, 60, 600, 600, 60, 60} {
+		total += increase * bytesPerMB
+		if err := storage.exec(`INSERT INTO network_history (timestamp, interface, bytes_sent, bytes_recv, packet_sent, packet_recv)
+			VALUES (?, 'eth0', ?, 0, 0, 0)`, start.Add(time.Duration(i)*time.Minute).Unix(), total); err != nil {
+			t.Fatalf("failed to insert network history: %v", err)
+		}
+	}
+
+	rule := models.AlertRule{
+		Name:      "network",
+		Metric:    "network",
+		Operator:  ">",
+		Threshold: 5,
+		Enabled:   true,
+		Actions:   []models.AlertAction{{Type: "webhook", Target: "ops"}},
+	}
+	result, err := as.BacktestRule(rule, start, start.Add(10*time.Minute))
+	if err != nil {
+		t.Fatalf("backtest failed: %v", err)
+	}
+
+	// 累计值始终超过阈值，按速率只在流量较高的两分钟内触发
+	if len(result.Firings) != 1 {
+		t.Fatalf("expected one firing, got %+v", result.Firings)
+	}
+	firing := result.Firings[0]
+	if !firing.StartsAt.Equal(start.Add(2*time.Minute)) || firing.Duration != 2*time.Minute || firing.Value != 10 {
+		t.Errorf("unexpected firing %+v", firing)
+	}
+}
+
+// backtestSample 回测使用的一次 cpu 采集，at 为相对回测开始时间的分钟数
+type backtestSample struct {
+	at    int
+	value float64
+}
+
+// backtestSpan 期望的触发区间，end 为 -1 表示回测结束时仍在触发
+type backtestSpan struct {
+	start, end int
+}
+
+func TestBacktestDurationAndHysteresis(t *testing.T) {
+	clearValue := 70.0
+	series := func(values ...float64) []backtestSample {
+		samples := make([]backtestSample, len(values))
+		for i, value := range values {
+			samples[i] = backtestSample{at: i, value: value}
+		}
+		return samples
+	}
+
+	cases := []struct {
+		name     string
+		duration time.Duration
+		clear    *float64
+		samples  []backtestSample
+		want     []backtestSpan
+	}{
+		{"fires immediately", 0, nil, series(90, 90, 10, 90), []backtestSpan{{0, 2}, {3, -1}}},
+		{"waits for duration", 2 * time.Minute, nil, series(90, 90, 90, 10, 90, 90), []backtestSpan{{2, 3}}},
+		{"false sample resets duration", 2 * time.Minute, nil, series(90, 90, 10, 90, 90, 90), []backtestSpan{{5, -1}}},
+		{"holds until clear threshold", 0, &clearValue, series(90, 75, 72, 65, 75, 90), []backtestSpan{{0, 3}, {5, -1}}},
+		{"gap resets duration", 2 * time.Minute, nil,
+			[]backtestSample{{0, 90}, {1, 90}, {8, 90}, {9, 90}, {10, 90}}, []backtestSpan{{10, -1}}},
+		{"gap keeps firing alert", 0, nil,
+			[]backtestSample{{0, 90}, {8, 90}, {9, 10}}, []backtestSpan{{0, 9}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			as, storage := newTestAlertingService(t)
+			start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+			for _, sample := range c.samples {
+				if err := storage.exec(`INSERT INTO cpu_history (timestamp, usage_percent, load1, load5, load15) VALUES (?, ?, 0, 0, 0)`,
+					start.Add(time.Duration(sample.at)*time.Minute).Unix(), sample.value); err != nil {
+					t.Fatalf("failed to insert cpu history: %v", err)
+				}
+			}
+
+			rule := models.AlertRule{
+				Name:           "cpu",
+				Metric:         "cpu",
+				Operator:       ">",
+				Threshold:      80,
+				ClearThreshold: c.clear,
+				Duration:       c.duration,
+				Enabled:        true,
+				Actions:        []models.AlertAction{{Type: "webhook", Target: "ops"}},
+			}
+			result, err := as.BacktestRule(rule, start, start.Add(time.Hour))
+			if err != nil {
+				t.Fatalf("backtest failed: %v", err)
+			}
+			if result.Samples != len(c.samples) {
+				t.Errorf("expected %d samples, got %d", len(c.samples), result.Samples)
+			}
+
+			if len(result.Firings) != len(c.want) {
+				t.Fatalf("expected %d firings, got %+v", len(c.want), result.Firings)
+			}
+			for i, want := range c.want {
+				firing := result.Firings[i]
+				if !firing.StartsAt.Equal(start.Add(time.Duration(want.start) * time.Minute)) {
+					t.Errorf("firing %d starts at %v, want minute %d", i, firing.StartsAt, want.start)
+				}
+				if want.end < 0 {
+					if firing.EndsAt != nil {
+						t.Errorf("firing %d should still be active, ended at %v", i, firing.EndsAt)
+					}
+					continue
+				}
+				if firing.EndsAt == nil || !firing.EndsAt.Equal(start.Add(time.Duration(want.end)*time.Minute)) {
+					t.Errorf("firing %d ends at %v, want minute %d", i, firing.EndsAt, want.end)
+				}
+			}
+		})
+	}
+}
+
+func TestBacktestRejectsUnsupportedRules(t *testing.T) {
+	as, _ := newTestAlertingService(t)
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	base := models.AlertRule{
+		Name:      "cpu",
+		Metric:    "cpu",
+		Operator:  ">",
+		Threshold: 80,
+		Enabled:   true,
+		Actions:   []models.AlertAction{{Type: "webhook", Target: "ops"}},
+	}
+
+	cases := []struct {
+		name   string
+		modify func(rule *models.AlertRule)
+		since  time.Time
+		until  time.Time
+	}{
+		{"expression", func(rule *models.AlertRule) { rule.Expression = "cpu.usage > 80" }, start, start.Add(time.Hour)},
+		{"anomaly", func(rule *models.AlertRule) {
+			rule.Type = ruleTypeAnomaly
+			rule.Anomaly = &models.AnomalyConfig{}
+		}, start, start.Add(time.Hour)},
+		{"metric without history", func(rule *models.AlertRule) { rule.Metric = "disk.iops" }, start, start.Add(time.Hour)},
+		{"empty range", func(rule *models.AlertRule) {}, start, start},
+		{"range too long", func(rule *models.AlertRule) {}, start, start.Add(maxBacktestRange + time.Hour)},
+	}
+	for _, c := range cases {
+		rule := base
+		c.modify(&rule)
+		if _, err := as.BacktestRule(rule, c.since, c.until); err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		}
+	}
+}
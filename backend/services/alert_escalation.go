@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// alertLevels 支持的告警级别
+var alertLevels = map[string]bool{
+	"info":     true,
+	"warning":  true,
+	"critical": true,
+}
+
+// validateEscalation 验证升级策略，升级步骤须按时间升序排列
+func validateEscalation(rule models.AlertRule) error {
+	policy := rule.Escalation
+	if policy == nil {
+		return nil
+	}
+	if policy.RepeatInterval < 0 {
+		return fmt.Errorf("repeat interval cannot be negative")
+	}
+
+	var last time.Duration
+	for i, step := range policy.Steps {
+		if step.After <= 0 {
+			return fmt.Errorf("escalation step %d: after must be positive", i+1)
+		}
+		if step.After <= last {
+			return fmt.Errorf("escalation step %d: steps must be in ascending order of after", i+1)
+		}
+		last = step.After
+
+		if step.Level != "" && !alertLevels[step.Level] {
+			return fmt.Errorf("escalation step %d: unknown level: %s", i+1, step.Level)
+		}
+		for _, action := range step.Actions {
+			if !alertActionTypes[action.Type] {
+				return fmt.Errorf("escalation step %d: unsupported action type: %s", i+1, action.Type)
+			}
+			if err := validateMessageTemplate(action.Template); err != nil {
+				return fmt.Errorf("escalation step %d: action %s: %w", i+1, action.Type, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// escalationActions 返回告警当前应使用的告警动作，即最近一个指定了动作的已执行升级步骤的动作
+func escalationActions(rule models.AlertRule, alert *models.Alert) []models.AlertAction {
+	if rule.Escalation == nil {
+		return rule.Actions
+	}
+
+	steps := rule.Escalation.Steps
+	for i := alert.Escalation - 1; i >= 0; i-- {
+		if i < len(steps) && len(steps[i].Actions) > 0 {
+			return steps[i].Actions
+		}
+	}
+	return rule.Actions
+}
+
+// escalate 按规则的升级策略处理未确认的活动告警：到期的升级步骤提升级别或更换动作并立即通知，
+// 否则按重复间隔再次通知。不会发送通知或处于维护窗口内的告警暂停升级，调用方需持有锁
+func (as *AlertingService) escalate(now time.Time) {
+	for _, alert := range as.active {
+		rule, ok := as.findRule(alert.RuleID)
+		if !ok || rule.Escalation == nil || !as.notifiable(alert) || as.inMaintenance(alert.RuleID, now) {
+			continue
+		}
+		policy := rule.Escalation
+
+		escalated := false
+		age := now.Sub(alert.CreatedAt)
+		for alert.Escalation < len(policy.Steps) && age >= policy.Steps[alert.Escalation].After {
+			step := policy.Steps[alert.Escalation]
+			alert.Escalation++
+			if step.Level != "" {
+				alert.Level = step.Level
+			}
+			escalated = true
+		}
+
+		if escalated {
+			log.Printf("Alert %s escalated to step %d (level: %s)", alert.RuleName, alert.Escalation, alert.Level)
+			as.eventMgr.EmitAlertEscalated(alert)
+			as.notify(alert)
+			continue
+		}
+
+		if policy.RepeatInterval <= 0 {
+			continue
+		}
+		last := alert.CreatedAt
+		if alert.LastNotifiedAt != nil {
+			last = *alert.LastNotifiedAt
+		}
+		if now.Sub(last) >= policy.RepeatInterval {
+			log.Printf("Repeating notification for unacknowledged alert %s", alert.RuleName)
+			as.notify(alert)
+		}
+	}
+}
+
+// recordNotified 记录告警最近一次发送通知的时间和升级状态，调用方需持有锁
+func (as *AlertingService) recordNotified(alert *models.Alert, now time.Time) {
+	alert.LastNotifiedAt = &now
+
+	if as.storage != nil {
+		if err := as.storage.UpdateAlertEscalation(alert); err != nil {
+			log.Printf("Failed to update alert %d: %v", alert.ID, err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// recordingChannel 记录收到的通知
+type recordingChannel struct {
+	mu   sync.Mutex
+	sent []recordedNotification
+}
+
+type recordedNotification struct {
+	target string
+	event  string
+	level  string
+}
+
+func (c *recordingChannel) Send(target string, notification models.Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, recordedNotification{target: target, event: notification.Event, level: notification.Alert.Level})
+	return nil
+}
+
+func (c *recordingChannel) snapshot() []recordedNotification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]recordedNotification(nil), c.sent...)
+}
+
+// fakeClock 可手动推进的时钟
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// escalationHarness 使用假时钟和记录渠道的告警服务
+type escalationHarness struct {
+	t       *testing.T
+	as      *AlertingService
+	ns      *NotificationService
+	channel *recordingChannel
+	clock   *fakeClock
+}
+
+func newEscalationHarness(t *testing.T, policy *models.EscalationPolicy) *escalationHarness {
+	t.Helper()
+
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	channel := &recordingChannel{}
+	ns := NewNotificationService(nil)
+	ns.RegisterChannel("webhook", channel)
+
+	as := NewAlertingService(nil, nil)
+	as.now = clock.Now
+	as.SetNotificationService(ns)
+
+	rule := models.AlertRule{
+		Name:       "cpu",
+		Metric:     "cpu",
+		Operator:   ">",
+		Threshold:  50,
+		Enabled:    true,
+		Escalation: policy,
+		Actions:    []models.AlertAction{{Type: "webhook", Target: "primary", Level: "warning"}},
+	}
+	if err := as.CreateRule(rule); err != nil {
+		t.Fatalf("failed to create rule: %v", err)
+	}
+
+	return &escalationHarness{t: t, as: as, ns: ns, channel: channel, clock: clock}
+}
+
+// check 推进时钟后检查告警，返回新发送的通知
+func (h *escalationHarness) check(advance time.Duration) []recordedNotification {
+	h.t.Helper()
+
+	before := len(h.channel.snapshot())
+	h.clock.Advance(advance)
+	if err := h.as.CheckAlerts(testMetrics(90)); err != nil {
+		h.t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	h.ns.Wait()
+	return h.channel.snapshot()[before:]
+}
+
+func TestEscalationRepeatInterval(t *testing.T) {
+	h := newEscalationHarness(t, &models.EscalationPolicy{RepeatInterval: 10 * time.Minute})
+
+	if sent := h.check(0); len(sent) != 1 || sent[0].event != notificationFiring {
+		t.Fatalf("expected the initial firing notification, got %+v", sent)
+	}
+
+	steps := []struct {
+		advance time.Duration
+		want    int
+	}{
+		{5 * time.Minute, 0},
+		{5 * time.Minute, 1}, // 距上次通知 10 分钟
+		{9 * time.Minute, 0},
+		{time.Minute, 1},
+	}
+	for i, step := range steps {
+		if sent := h.check(step.advance); len(sent) != step.want {
+			t.Fatalf("step %d: expected %d notifications, got %+v", i, step.want, sent)
+		}
+	}
+
+	// 确认后不再重复通知
+	alerts := h.as.GetActiveAlerts()
+	if err := h.as.AcknowledgeAlert(alerts[0].ID, "tester", ""); err != nil {
+		t.Fatalf("failed to acknowledge: %v", err)
+	}
+	if sent := h.check(time.Hour); len(sent) != 0 {
+		t.Errorf("acknowledged alert was repeated: %+v", sent)
+	}
+}
+
+func TestEscalationSteps(t *testing.T) {
+	h := newEscalationHarness(t, &models.EscalationPolicy{Steps: []models.EscalationStep{
+		{After: 5 * time.Minute, Level: "critical"},
+		{After: 15 * time.Minute, Actions: []models.AlertAction{{Type: "webhook", Target: "oncall", Level: "critical"}}},
+	}})
+
+	if sent := h.check(0); len(sent) != 1 || sent[0].target != "primary" || sent[0].level != "warning" {
+		t.Fatalf("expected the initial notification to primary, got %+v", sent)
+	}
+	if sent := h.check(4 * time.Minute); len(sent) != 0 {
+		t.Fatalf("escalated too early: %+v", sent)
+	}
+
+	// 第一步只提升级别，沿用原来的动作
+	sent := h.check(time.Minute)
+	if len(sent) != 1 || sent[0].target != "primary" || sent[0].level != "critical" {
+		t.Fatalf("expected escalation to critical on primary, got %+v", sent)
+	}
+	if alert := h.as.GetActiveAlerts()[0]; alert.Escalation != 1 || alert.Level != "critical" {
+		t.Errorf("unexpected alert state after first step: escalation %d, level %s", alert.Escalation, alert.Level)
+	}
+
+	// 第二步改用新的动作
+	if sent := h.check(9 * time.Minute); len(sent) != 0 {
+		t.Fatalf("escalated too early: %+v", sent)
+	}
+	sent = h.check(time.Minute)
+	if len(sent) != 1 || sent[0].target != "oncall" {
+		t.Fatalf("expected escalation to oncall, got %+v", sent)
+	}
+
+	// 所有步骤执行后没有重复间隔时不再通知
+	if sent := h.check(time.Hour); len(sent) != 0 {
+		t.Errorf("unexpected notifications after the last step: %+v", sent)
+	}
+}
+
+func TestEscalationPausedDuringMaintenance(t *testing.T) {
+	h := newEscalationHarness(t, &models.EscalationPolicy{
+		RepeatInterval: 10 * time.Minute,
+		Steps:          []models.EscalationStep{{After: 30 * time.Minute, Level: "critical"}},
+	})
+
+	if sent := h.check(0); len(sent) != 1 {
+		t.Fatalf("expected the initial notification, got %+v", sent)
+	}
+
+	// 告警触发后才开始的维护窗口，每分钟都匹配开始时间，持续生效
+	window, err := h.as.CreateMaintenanceWindow(models.MaintenanceWindow{
+		Name:     "deploy",
+		Schedule: "* * * * *",
+		Duration: time.Hour,
+		Timezone: "UTC",
+		Enabled:  true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create maintenance window: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if sent := h.check(10 * time.Minute); len(sent) != 0 {
+			t.Fatalf("repeat or escalation sent during maintenance: %+v", sent)
+		}
+	}
+	if alert := h.as.GetActiveAlerts()[0]; alert.Escalation != 0 {
+		t.Errorf("alert escalated during maintenance to step %d", alert.Escalation)
+	}
+
+	if err := h.as.DeleteMaintenanceWindow(window.ID); err != nil {
+		t.Fatalf("failed to delete maintenance window: %v", err)
+	}
+	// 维护结束后补发通知，并继续执行到期的升级步骤
+	sent := h.check(time.Minute)
+	if len(sent) == 0 {
+		t.Fatal("expected notifications to resume after maintenance")
+	}
+	if alert := h.as.GetActiveAlerts()[0]; alert.Escalation != 1 || alert.Level != "critical" {
+		t.Errorf("expected escalation to resume after maintenance, got step %d level %s", alert.Escalation, alert.Level)
+	}
+}
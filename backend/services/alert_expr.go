@@ -0,0 +1,671 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"system-monitor/backend/models"
+)
+
+// 告警表达式示例：
+//
+//	cpu.usage > 85 && cpu.load15 > 4
+//	avg_over(cpu.usage, 5m) > 70 || memory.used_percent >= 95
+//
+// 支持 + - * / 算术运算、比较运算、&& || ! 逻辑运算和括号。
+// 多目标指标（磁盘、网络）按规则的目标选择器过滤后取最大值。
+// avg_over、min_over、max_over 统计最近一段时间的历史数据，需要存储服务。
+// 时间窗口最长为 maxExprWindow，超过 rawHistoryMaxRange 时使用汇总数据。
+
+// exprField 从采集数据中读取表达式变量
+type exprField func(data map[string]interface{}) ([]metricSample, error)
+
+// cpuField 读取CPU字段
+func cpuField(get func(*models.CPUInfo) float64) exprField {
+	return func(data map[string]interface{}) ([]metricSample, error) {
+		if cpuInfo, ok := data["cpu"].(*models.CPUInfo); ok && cpuInfo != nil {
+			return []metricSample{{Value: get(cpuInfo)}}, nil
+		}
+		return nil, fmt.Errorf("metric data not found: cpu")
+	}
+}
+
+// memoryField 读取内存字段
+func memoryField(get func(*models.MemoryInfo) float64) exprField {
+	return func(data map[string]interface{}) ([]metricSample, error) {
+		if memInfo, ok := data["memory"].(*models.MemoryInfo); ok && memInfo != nil {
+			return []metricSample{{Value: get(memInfo)}}, nil
+		}
+		return nil, fmt.Errorf("metric data not found: memory")
+	}
+}
+
+// diskField 读取各挂载点的磁盘字段
+func diskField(get func(models.DiskInfo) float64) exprField {
+	return func(data map[string]interface{}) ([]metricSample, error) {
+		disks, ok := data["disk"].([]models.DiskInfo)
+		if !ok {
+			return nil, fmt.Errorf("metric data not found: disk")
+		}
+		samples := make([]metricSample, 0, len(disks))
+		for _, disk := range disks {
+			if !alertableDisk(disk) {
+				continue
+			}
+			samples = append(samples, metricSample{
+				Target: disk.Mountpoint,
+				Names:  []string{disk.Mountpoint, disk.Device, filepath.Base(disk.Device)},
+				Value:  get(disk),
+			})
+		}
+		return samples, nil
+	}
+}
+
+// metricField 复用告警指标的取值
+func metricField(metric string) exprField {
+	return func(data map[string]interface{}) ([]metricSample, error) {
+		return getMetricSamples(metric, data)
+	}
+}
+
+// exprFields 表达式中可用的变量
+var exprFields = map[string]exprField{
+	"cpu.usage":           cpuField(func(c *models.CPUInfo) float64 { return c.Usage }),
+	"cpu.load1":           cpuField(func(c *models.CPUInfo) float64 { return c.Load1 }),
+	"cpu.load5":           cpuField(func(c *models.CPUInfo) float64 { return c.Load5 }),
+	"cpu.load15":          cpuField(func(c *models.CPUInfo) float64 { return c.Load15 }),
+	"memory.used_percent": memoryField(func(m *models.MemoryInfo) float64 { return m.UsedPercent }),
+	"memory.swap_percent": memoryField(func(m *models.MemoryInfo) float64 { return m.SwapPercent }),
+	"memory.available":    memoryField(func(m *models.MemoryInfo) float64 { return float64(m.Available) }),
+	"memory.free":         memoryField(func(m *models.MemoryInfo) float64 { return float64(m.Free) }),
+	"memory.used":         memoryField(func(m *models.MemoryInfo) float64 { return float64(m.Used) }),
+	"memory.swap_used":    memoryField(func(m *models.MemoryInfo) float64 { return float64(m.SwapUsed) }),
+	"disk.used_percent":   diskField(func(d models.DiskInfo) float64 { return d.UsedPercent }),
+	"disk.free":           diskField(func(d models.DiskInfo) float64 { return float64(d.Free) }),
+	"disk.used":           diskField(func(d models.DiskInfo) float64 { return float64(d.Used) }),
+	"disk.read_rate":      metricField("disk.read_rate"),
+	"disk.write_rate":     metricField("disk.write_rate"),
+	"disk.iops":           metricField("disk.iops"),
+	"network.rx_rate":     metricField("network.rx_rate"),
+	"network.tx_rate":     metricField("network.tx_rate"),
+	"network.errors_rate": metricField("network.errors_rate"),
+	"network.errors":      metricField("network_errors"), // 与 network.errors_rate 相同，保留以兼容已有表达式
+	"processes.count":     metricField("processes"),
+}
+
+// maxExprWindow 历史聚合函数的最长时间窗口
+const maxExprWindow = 7 * 24 * time.Hour
+
+// exprHistoryFuncs 读取历史数据的聚合函数
+var exprHistoryFuncs = map[string]bool{
+	"avg_over": true,
+	"min_over": true,
+	"max_over": true,
+}
+
+// exprNode 表达式语法树节点
+type exprNode interface {
+	eval(ctx *exprContext) (float64, error)
+}
+
+// exprContext 表达式求值上下文
+type exprContext struct {
+	eval     *ruleEvaluator
+	rule     models.AlertRule
+	data     map[string]interface{}
+	now      time.Time
+	value    float64 // 第一个比较运算的左值，作为告警的当前值
+	hasValue bool
+}
+
+type numberNode struct {
+	value float64
+}
+
+type identNode struct {
+	name string
+}
+
+type unaryNode struct {
+	op string
+	x  exprNode
+}
+
+type binaryNode struct {
+	op   string
+	l, r exprNode
+}
+
+type callNode struct {
+	fn     string
+	metric string
+	field  rollupField
+	source rollupSource
+	window time.Duration
+}
+
+func (n numberNode) eval(ctx *exprContext) (float64, error) {
+	return n.value, nil
+}
+
+func (n identNode) eval(ctx *exprContext) (float64, error) {
+	samples, err := exprFields[n.name](ctx.data)
+	if err != nil {
+		return 0, err
+	}
+	return reduceSamples(ctx.rule, n.name, samples)
+}
+
+func (n unaryNode) eval(ctx *exprContext) (float64, error) {
+	x, err := n.x.eval(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if n.op == "!" {
+		return boolValue(x == 0), nil
+	}
+	return -x, nil
+}
+
+func (n binaryNode) eval(ctx *exprContext) (float64, error) {
+	l, err := n.l.eval(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// 逻辑运算短路求值
+	switch n.op {
+	case "&&":
+		if l == 0 {
+			return 0, nil
+		}
+	case "||":
+		if l != 0 {
+			return 1, nil
+		}
+	}
+
+	r, err := n.r.eval(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case "&&", "||":
+		return boolValue(r != 0), nil
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	}
+
+	if !ctx.hasValue {
+		ctx.value = l
+		ctx.hasValue = true
+	}
+	return boolValue(ctx.eval.as.evaluateCondition(l, n.op, r)), nil
+}
+
+func (n callNode) eval(ctx *exprContext) (float64, error) {
+	if ctx.eval.storage == nil {
+		return 0, fmt.Errorf("%s requires history storage", n.fn)
+	}
+
+	stats, err := ctx.eval.storage.metricWindow(n.source, n.field, ctx.now, n.window)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read history of %s: %w", n.metric, err)
+	}
+
+	// 合并选中序列的统计值
+	var total windowStats
+	for series, s := range stats {
+		if n.source.SeriesCol != "" && !targetSelected(ctx.rule.Target, []string{series}) {
+			continue
+		}
+		if total.samples == 0 || s.min < total.min {
+			total.min = s.min
+		}
+		if total.samples == 0 || s.max > total.max {
+			total.max = s.max
+		}
+		total.sum += s.sum
+		total.samples += s.samples
+	}
+	if total.samples == 0 {
+		return 0, fmt.Errorf("no history for %s in the last %s", n.metric, n.window)
+	}
+
+	switch n.fn {
+	case "min_over":
+		return total.min, nil
+	case "max_over":
+		return total.max, nil
+	default:
+		return total.sum / float64(total.samples), nil
+	}
+}
+
+// boolValue 将布尔值转换为表达式中的数值
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// targetSelected 检查目标是否被规则的选择器选中，空选择器和 any/all 选中全部目标
+func targetSelected(selector string, names []string) bool {
+	selector = strings.TrimSpace(selector)
+	switch selector {
+	case "", "*", targetAny, targetAll:
+		return true
+	}
+	return matchTarget(selector, names)
+}
+
+// reduceSamples 多目标变量取选中目标中的最大值
+func reduceSamples(rule models.AlertRule, name string, samples []metricSample) (float64, error) {
+	value, found := 0.0, false
+	for _, sample := range samples {
+		if sample.Target != "" && !targetSelected(rule.Target, sample.Names) {
+			continue
+		}
+		if !found || sample.Value > value {
+			value = sample.Value
+			found = true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no target selected for %s", name)
+	}
+	return value, nil
+}
+
+// evaluateExpression 对规则表达式求值，返回条件是否成立和告警的当前值
+func (e *ruleEvaluator) evaluateExpression(rule models.AlertRule, data map[string]interface{}) (bool, float64, error) {
+	node, err := e.expression(rule.Expression)
+	if err != nil {
+		return false, 0, err
+	}
+
+	ctx := &exprContext{eval: e, rule: rule, data: data, now: e.now}
+	result, err := node.eval(ctx)
+	if err != nil {
+		return false, 0, err
+	}
+
+	value := result
+	if ctx.hasValue {
+		value = ctx.value
+	}
+	return result != 0, value, nil
+}
+
+// expression 返回解析后的表达式，同一表达式只解析一次。语法树不保存求值状态，可以在多次评估间共享
+func (e *ruleEvaluator) expression(input string) (exprNode, error) {
+	e.as.cacheMu.Lock()
+	node, ok := e.as.exprCache[input]
+	e.as.cacheMu.Unlock()
+	if ok {
+		return node, nil
+	}
+
+	node, err := parseExpression(input)
+	if err != nil {
+		return nil, err
+	}
+
+	e.as.cacheMu.Lock()
+	e.as.exprCache[input] = node
+	e.as.cacheMu.Unlock()
+	return node, nil
+}
+
+// windowStats 序列在时间窗口内的统计值
+type windowStats struct {
+	min, max, sum float64
+	samples       int64
+}
+
+// metricWindow 在数据库中统计字段在最近 window 内的最小值、最大值和总和，按序列分组。
+// 窗口不超过 rawHistoryMaxRange 时统计原始数据，否则统计对应层级的汇总数据
+func (s *StorageService) metricWindow(src rollupSource, field rollupField, now time.Time, window time.Duration) (map[string]windowStats, error) {
+	since := now.Add(-window).Unix()
+
+	var query string
+	var args []interface{}
+	if tier := selectRollupTier(window); tier != nil {
+		query = fmt.Sprintf(`SELECT series, MIN(min_value), MAX(max_value), SUM(avg_value * samples), SUM(samples)
+			FROM %s WHERE metric = ? AND field = ? AND bucket >= ? GROUP BY series`, tier.Table)
+		args = []interface{}{src.Metric, field.Key, since}
+	} else {
+		seriesCol := "''"
+		if src.SeriesCol != "" {
+			seriesCol = src.SeriesCol
+		}
+		query = fmt.Sprintf("SELECT %s, MIN(%s), MAX(%s), SUM(%s), COUNT(*) FROM %s WHERE timestamp >= ? GROUP BY 1",
+			seriesCol, field.Column, field.Column, field.Column, src.Table)
+		args = []interface{}{since}
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]windowStats)
+	for rows.Next() {
+		var series string
+		var st windowStats
+		if err := rows.Scan(&series, &st.min, &st.max, &st.sum, &st.samples); err != nil {
+			return nil, err
+		}
+		stats[series] = st
+	}
+
+	return stats, rows.Err()
+}
+
+// exprOperators 表达式支持的运算符和分隔符
+var exprOperators = map[string]bool{
+	"&&": true, "||": true, "!": true,
+	">": true, ">=": true, "<": true, "<=": true, "==": true, "!=": true,
+	"+": true, "-": true, "*": true, "/": true,
+	"(": true, ")": true, ",": true,
+}
+
+// exprToken 词法单元
+type exprToken struct {
+	kind string // number、duration、ident、op、end
+	text string
+	pos  int
+}
+
+// tokenizeExpression 将表达式拆分为词法单元
+func tokenizeExpression(input string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			kind := "number"
+			// 数字后紧跟单位时为时长，如 5m、30s、1h
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				kind = "duration"
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: kind, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: "ident", text: string(runes[start:i]), pos: start})
+		default:
+			op := string(c)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "&&", "||", ">=", "<=", "==", "!=":
+					op = two
+				}
+			}
+			if !exprOperators[op] {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, exprToken{kind: "op", text: op, pos: i})
+			i += len([]rune(op))
+		}
+	}
+
+	return append(tokens, exprToken{kind: "end", pos: len(runes)}), nil
+}
+
+// exprParser 递归下降解析器
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+// parseExpression 解析告警表达式
+func parseExpression(input string) (exprNode, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, fmt.Errorf("expression cannot be empty")
+	}
+
+	tokens, err := tokenizeExpression(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != "end" {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return node, nil
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != "end" {
+		p.pos++
+	}
+	return tok
+}
+
+// accept 当前词法单元是指定运算符时前进
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != "op" {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// expect 要求当前词法单元是指定运算符
+func (p *exprParser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		tok := p.peek()
+		return fmt.Errorf("expected %q at position %d", op, tok.pos)
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	return p.parseBinary(p.parseNot, "&&")
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if _, ok := p.accept("!"); ok {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "!", x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	l, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.accept(">", ">=", "<", "<=", "==", "!="); ok {
+		r, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: op, l: l, r: r}, nil
+	}
+	return l, nil
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	return p.parseBinary(p.parseTerm, "+", "-")
+}
+
+func (p *exprParser) parseTerm() (exprNode, error) {
+	return p.parseBinary(p.parseUnary, "*", "/")
+}
+
+// parseBinary 解析左结合的二元运算
+func (p *exprParser) parseBinary(operand func() (exprNode, error), ops ...string) (exprNode, error) {
+	l, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return l, nil
+		}
+		r, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: op, l: l, r: r}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if _, ok := p.accept("-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "-", x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	if _, ok := p.accept("("); ok {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+
+	tok := p.next()
+	switch tok.kind {
+	case "number":
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return numberNode{value: value}, nil
+	case "ident":
+		if exprHistoryFuncs[tok.text] {
+			return p.parseCall(tok)
+		}
+		if _, ok := exprFields[tok.text]; !ok {
+			return nil, fmt.Errorf("unknown variable %q at position %d", tok.text, tok.pos)
+		}
+		return identNode{name: tok.text}, nil
+	case "end":
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+}
+
+// parseCall 解析历史聚合函数，如 avg_over(cpu.usage, 5m)
+func (p *exprParser) parseCall(fn exprToken) (exprNode, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	metric := p.next()
+	if metric.kind != "ident" {
+		return nil, fmt.Errorf("%s expects a variable at position %d", fn.text, metric.pos)
+	}
+	src, field, ok := findHistoryField(metric.text)
+	if !ok {
+		return nil, fmt.Errorf("%s has no history at position %d", metric.text, metric.pos)
+	}
+
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+
+	window := p.next()
+	if window.kind != "duration" {
+		return nil, fmt.Errorf("%s expects a duration such as 5m at position %d", fn.text, window.pos)
+	}
+	duration, err := time.ParseDuration(window.text)
+	if err != nil || duration <= 0 {
+		return nil, fmt.Errorf("invalid duration %q at position %d", window.text, window.pos)
+	}
+	if duration > maxExprWindow {
+		return nil, fmt.Errorf("duration %q at position %d exceeds the maximum of %s", window.text, window.pos, maxExprWindow)
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return callNode{fn: fn.text, metric: metric.text, field: field, source: src, window: duration}, nil
+}
+
+// findHistoryField 查找变量对应的历史数据列，变量名为“指标.字段”
+func findHistoryField(name string) (rollupSource, rollupField, bool) {
+	parts := strings.SplitN(name, ".", 2)
+	if len(parts) != 2 {
+		return rollupSource{}, rollupField{}, false
+	}
+
+	src, ok := findRollupSource(parts[0])
+	if !ok {
+		return rollupSource{}, rollupField{}, false
+	}
+	for _, field := range src.Fields {
+		if field.Key == parts[1] {
+			return src, field, true
+		}
+	}
+	return rollupSource{}, rollupField{}, false
+}
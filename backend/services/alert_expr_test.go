@@ -0,0 +1,74 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+func TestExpressionHistoryWindows(t *testing.T) {
+	as, storage := newTestAlertingService(t)
+	now := time.Now()
+
+	// 最近几分钟的原始数据：10、20、30
+	for i, usage := range []float64{10, 20, 30} {
+		if _, err := storage.db.Exec(`INSERT INTO cpu_history (timestamp, usage_percent, load1, load5, load15) VALUES (?, ?, 0, 0, 0)`,
+			now.Add(-time.Duration(i+1)*time.Minute).Unix(), usage); err != nil {
+			t.Fatalf("failed to insert history: %v", err)
+		}
+	}
+
+	// 几小时前的汇总数据：两个桶的均值为 40 和 70，样本数为 1 和 3
+	for _, row := range []struct {
+		ago           time.Duration
+		samples       int
+		min, avg, max float64
+	}{
+		{3 * time.Hour, 1, 40, 40, 40},
+		{2 * time.Hour, 3, 50, 70, 90},
+	} {
+		if _, err := storage.db.Exec(`INSERT INTO metric_rollup_1m (bucket, metric, series, field, samples, min_value, avg_value, max_value, p95_value)
+			VALUES (?, 'cpu', '', 'usage', ?, ?, ?, ?, ?)`, now.Add(-row.ago).Unix(), row.samples, row.min, row.avg, row.max, row.max); err != nil {
+			t.Fatalf("failed to insert rollup: %v", err)
+		}
+	}
+
+	eval := &ruleEvaluator{as: as, storage: storage, now: now}
+	cases := []struct {
+		expr string
+		want float64
+	}{
+		{"avg_over(cpu.usage, 5m) > 0", 20},
+		{"min_over(cpu.usage, 5m) > 0", 10},
+		{"max_over(cpu.usage, 5m) > 0", 30},
+		{"avg_over(cpu.usage, 6h) > 0", 62.5},
+		{"min_over(cpu.usage, 6h) > 0", 40},
+		{"max_over(cpu.usage, 6h) > 0", 90},
+	}
+	for _, c := range cases {
+		_, value, err := eval.evaluateExpression(models.AlertRule{Expression: c.expr}, nil)
+		if err != nil {
+			t.Errorf("%s: %v", c.expr, err)
+			continue
+		}
+		if value != c.want {
+			t.Errorf("%s = %v, want %v", c.expr, value, c.want)
+		}
+	}
+
+	// 同一表达式只解析一次
+	if _, ok := as.exprCache["avg_over(cpu.usage, 5m) > 0"]; !ok {
+		t.Error("parsed expression was not cached")
+	}
+}
+
+func TestExpressionWindowLimit(t *testing.T) {
+	if _, err := parseExpression("avg_over(cpu.usage, 168h) > 50"); err != nil {
+		t.Errorf("window of %s should be allowed: %v", maxExprWindow, err)
+	}
+	if _, err := parseExpression("avg_over(cpu.usage, 169h) > 50"); err == nil || !strings.Contains(err.Error(), "exceeds the maximum") {
+		t.Errorf("expected window limit error, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// 抖动检测的默认配置
+const (
+	defaultFlapChanges = 6
+	defaultFlapWindow  = 10 * time.Minute
+)
+
+// flapState 告警实例的抖动状态
+type flapState struct {
+	changes  []time.Time   // 窗口内的状态变化时间
+	flapping bool          // 是否处于抖动状态
+	notified *models.Alert // 已发送触发通知但尚未发送解决通知的告警
+}
+
+// flapConfig 返回补齐默认值后的抖动检测配置
+func flapConfig(rule models.AlertRule) models.FlapConfig {
+	cfg := *rule.Flap
+	if cfg.Changes <= 0 {
+		cfg.Changes = defaultFlapChanges
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultFlapWindow
+	}
+	return cfg
+}
+
+// validateHysteresis 验证恢复阈值，恢复阈值必须位于告警阈值的恢复一侧
+func validateHysteresis(rule models.AlertRule) error {
+	if rule.ClearThreshold == nil {
+		return nil
+	}
+	if rule.Expression != "" || rule.Type == ruleTypeAnomaly {
+		return fmt.Errorf("clear threshold is only supported for threshold rules")
+	}
+
+	clearValue := *rule.ClearThreshold
+	switch rule.Operator {
+	case ">", ">=":
+		if clearValue > rule.Threshold {
+			return fmt.Errorf("clear threshold must not be greater than threshold")
+		}
+	case "<", "<=":
+		if clearValue < rule.Threshold {
+			return fmt.Errorf("clear threshold must not be less than threshold")
+		}
+	default:
+		return fmt.Errorf("clear threshold is not supported for operator %s", rule.Operator)
+	}
+
+	return nil
+}
+
+// validateFlap 验证抖动检测配置
+func validateFlap(rule models.AlertRule) error {
+	if rule.Flap == nil {
+		return nil
+	}
+	if rule.Flap.Changes < 0 || rule.Flap.Changes == 1 {
+		return fmt.Errorf("flap changes must be at least 2")
+	}
+	if rule.Flap.Window < 0 {
+		return fmt.Errorf("flap window cannot be negative")
+	}
+	return nil
+}
+
+// holdsActive 检查设置了恢复阈值的活动告警是否仍应保持，调用方需持有锁
+func (as *AlertingService) holdsActive(rule models.AlertRule, value float64) bool {
+	return rule.ClearThreshold != nil && as.evaluateCondition(value, rule.Operator, *rule.ClearThreshold)
+}
+
+// recordStateChange 记录告警触发或解决，窗口内状态变化次数达到阈值时进入抖动状态，调用方需持有锁
+func (as *AlertingService) recordStateChange(alert *models.Alert, now time.Time) {
+	rule, ok := as.findRule(alert.RuleID)
+	if !ok || rule.Flap == nil {
+		return
+	}
+
+	cfg := flapConfig(rule)
+	key := alertKey(alert.RuleID, alert.Target)
+	state, ok := as.flaps[key]
+	if !ok {
+		state = &flapState{}
+		as.flaps[key] = state
+	}
+
+	state.changes = append(pruneChanges(state.changes, now.Add(-cfg.Window)), now)
+	alert.Flapping = state.flapping
+	if state.flapping || len(state.changes) < cfg.Changes {
+		return
+	}
+
+	state.flapping = true
+	alert.Flapping = true
+	if active, ok := as.active[key]; ok {
+		active.Flapping = true
+	}
+
+	log.Printf("Alert %s is flapping: %d state changes in %s, notifications suppressed",
+		alert.RuleName, len(state.changes), cfg.Window)
+	as.eventMgr.EmitAlertFlapping(alert)
+}
+
+// updateFlapping 检查规则各告警实例是否恢复稳定，稳定后补发与当前状态一致的通知，调用方需持有锁
+func (as *AlertingService) updateFlapping(rule models.AlertRule, now time.Time) {
+	if rule.Flap == nil {
+		return
+	}
+
+	cfg := flapConfig(rule)
+	prefix := alertKey(rule.ID, "")
+	for key, state := range as.flaps {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		state.changes = pruneChanges(state.changes, now.Add(-cfg.Window))
+		if !state.flapping {
+			if len(state.changes) == 0 && state.notified == nil {
+				delete(as.flaps, key)
+			}
+			continue
+		}
+		if len(state.changes) > 0 {
+			continue
+		}
+
+		state.flapping = false
+		active := as.active[key]
+		log.Printf("Alert rule %s stabilized, notifications resumed", rule.Name)
+
+		// 抖动期间被抑制的通知可能使外部看到的状态与实际不一致
+		if active != nil {
+			active.Flapping = false
+			as.eventMgr.EmitAlertFlapping(active)
+			if state.notified != active {
+				if state.notified != nil {
+					as.notifyResolved(state.notified)
+				}
+				as.notify(active)
+			}
+		} else if state.notified != nil {
+			notified := state.notified
+			notified.Flapping = false
+			as.eventMgr.EmitAlertFlapping(notified)
+			as.notifyResolved(notified)
+		}
+	}
+}
+
+// isFlapping 检查告警实例是否处于抖动状态，调用方需持有锁
+func (as *AlertingService) isFlapping(alert *models.Alert) bool {
+	state, ok := as.flaps[alertKey(alert.RuleID, alert.Target)]
+	return ok && state.flapping
+}
+
+// markNotified 记录告警实例最近一次发送的通知，调用方需持有锁
+func (as *AlertingService) markNotified(alert *models.Alert, firing bool) {
+	state, ok := as.flaps[alertKey(alert.RuleID, alert.Target)]
+	if !ok {
+		return
+	}
+	if firing {
+		state.notified = alert
+	} else if state.notified == alert {
+		state.notified = nil
+	}
+}
+
+// clearFlapping 清除规则所有告警实例的抖动状态，调用方需持有锁
+func (as *AlertingService) clearFlapping(ruleID int64) {
+	prefix := alertKey(ruleID, "")
+	for key := range as.flaps {
+		if strings.HasPrefix(key, prefix) {
+			delete(as.flaps, key)
+		}
+	}
+}
+
+// pruneChanges 丢弃早于 since 的状态变化
+func pruneChanges(changes []time.Time, since time.Time) []time.Time {
+	i := 0
+	for i < len(changes) && changes[i].Before(since) {
+		i++
+	}
+	return changes[i:]
+}
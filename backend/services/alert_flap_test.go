@@ -0,0 +1,135 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// newFlapHarness 创建使用指定 cpu 规则的告警服务，每次检查间隔一分钟
+func newFlapHarness(t *testing.T, rule models.AlertRule) *escalationHarness {
+	t.Helper()
+
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	channel := &recordingChannel{}
+	ns := NewNotificationService(nil)
+	ns.RegisterChannel("webhook", channel)
+
+	as := NewAlertingService(nil, nil)
+	as.now = clock.Now
+	as.SetNotificationService(ns)
+
+	rule.Name = "cpu"
+	rule.Metric = "cpu"
+	rule.Operator = ">"
+	rule.Enabled = true
+	rule.Actions = []models.AlertAction{{Type: "webhook", Target: "primary", Level: "warning"}}
+	if err := as.CreateRule(rule); err != nil {
+		t.Fatalf("failed to create rule: %v", err)
+	}
+
+	return &escalationHarness{t: t, as: as, ns: ns, channel: channel, clock: clock}
+}
+
+// checkValue 推进一分钟后按指定 cpu 使用率检查告警，返回活动告警，没有时返回 nil
+func (h *escalationHarness) checkValue(cpu float64) *models.Alert {
+	h.t.Helper()
+
+	h.clock.Advance(time.Minute)
+	if err := h.as.CheckAlerts(testMetrics(cpu)); err != nil {
+		h.t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	h.ns.Wait()
+
+	for _, alert := range h.as.GetActiveAlerts() {
+		if alert.Status == "active" {
+			return &alert
+		}
+	}
+	return nil
+}
+
+func TestClearThresholdHysteresis(t *testing.T) {
+	clearValue := 70.0
+	cases := []struct {
+		name   string
+		clear  *float64
+		values []float64
+		want   []bool // 每次检查后是否有活动告警
+	}{
+		{"without clear threshold", nil, []float64{85, 75, 85, 65}, []bool{true, false, true, false}},
+		{"holds above clear threshold", &clearValue, []float64{85, 75, 72, 70, 65, 75, 85}, []bool{true, true, true, false, false, false, true}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := newFlapHarness(t, models.AlertRule{Threshold: 80, ClearThreshold: c.clear})
+			for i, value := range c.values {
+				if active := h.checkValue(value) != nil; active != c.want[i] {
+					t.Errorf("check %d (value %v): active = %v, want %v", i, value, active, c.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateHysteresis(t *testing.T) {
+	cases := []struct {
+		operator  string
+		threshold float64
+		clear     float64
+		ok        bool
+	}{
+		{">", 80, 70, true},
+		{">", 80, 80, true},
+		{">", 80, 90, false},
+		{"<", 20, 30, true},
+		{"<", 20, 10, false},
+		{"==", 80, 80, false},
+	}
+	for _, c := range cases {
+		clearValue := c.clear
+		rule := models.AlertRule{Operator: c.operator, Threshold: c.threshold, ClearThreshold: &clearValue}
+		if err := validateHysteresis(rule); (err == nil) != c.ok {
+			t.Errorf("%s %v clear %v: expected ok=%v, got %v", c.operator, c.threshold, c.clear, c.ok, err)
+		}
+	}
+}
+
+func TestFlappingSuppressesNotificationsUntilStable(t *testing.T) {
+	h := newFlapHarness(t, models.AlertRule{Threshold: 50, Flap: &models.FlapConfig{Changes: 4, Window: 10 * time.Minute}})
+
+	// 前 4 次状态变化正常通知，第 4 次变化后进入抖动状态
+	for i, value := range []float64{90, 10, 90, 10} {
+		h.checkValue(value)
+		if sent := h.channel.snapshot(); len(sent) != i+1 {
+			t.Fatalf("check %d: expected %d notifications, got %+v", i, i+1, sent)
+		}
+	}
+
+	alert := h.checkValue(90)
+	if alert == nil || !alert.Flapping {
+		t.Fatalf("expected a flapping active alert, got %+v", alert)
+	}
+	h.checkValue(10)
+
+	// 最后一次状态变化（再次触发）之后的整个窗口内保持抖动状态
+	for i := 0; i <= 10; i++ {
+		if alert := h.checkValue(90); alert == nil || !alert.Flapping {
+			t.Fatalf("minute %d: expected the alert to keep flapping, got %+v", i, alert)
+		}
+	}
+	if sent := h.channel.snapshot(); len(sent) != 4 {
+		t.Fatalf("expected no notifications while flapping, got %+v", sent)
+	}
+
+	// 窗口内没有状态变化后恢复稳定，补发当前状态的触发通知
+	alert = h.checkValue(90)
+	if alert == nil || alert.Flapping {
+		t.Fatalf("expected a stable active alert, got %+v", alert)
+	}
+	sent := h.channel.snapshot()
+	if len(sent) != 5 || sent[4].event != notificationFiring {
+		t.Errorf("expected a firing notification after stabilizing, got %+v", sent)
+	}
+}
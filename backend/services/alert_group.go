@@ -0,0 +1,146 @@
+package services
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// alertGroup 等待合并发送的一组告警通知
+type alertGroup struct {
+	event   string
+	labels  map[string]string
+	alerts  []*models.Alert
+	started time.Time
+}
+
+// SetGrouping 设置告警通知分组，wait 内标签 by 相同的通知合并发送，wait 为0时不分组
+func (as *AlertingService) SetGrouping(by []string, wait time.Duration) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.groupBy = append([]string(nil), by...)
+	as.groupWait = wait
+}
+
+// groupLabels 计算告警用于分组的标签，调用方需持有锁
+func (as *AlertingService) groupLabels(alert *models.Alert) map[string]string {
+	rule, _ := as.findRule(alert.RuleID)
+	all := messageLabels(rule, as.host, alert.Target, alert.Level)
+	all["rule"] = alert.RuleName
+
+	labels := make(map[string]string, len(as.groupBy))
+	for _, name := range as.groupBy {
+		labels[name] = all[name]
+	}
+	return labels
+}
+
+// enqueue 将通知加入所属分组，分组等待时间到达后由 flushGroups 发送，调用方需持有锁
+func (as *AlertingService) enqueue(alert *models.Alert, event string) {
+	labels := as.groupLabels(alert)
+	parts := []string{event}
+	for _, name := range as.groupBy {
+		parts = append(parts, name+"="+labels[name])
+	}
+	key := strings.Join(parts, "\x00")
+
+	group, ok := as.groups[key]
+	if !ok {
+		group = &alertGroup{event: event, labels: labels, started: as.now()}
+		as.groups[key] = group
+	}
+	for _, queued := range group.alerts {
+		if queued == alert {
+			return
+		}
+	}
+	group.alerts = append(group.alerts, alert)
+}
+
+// dequeueFiring 从分组中移除尚未发送的触发通知，返回是否移除，调用方需持有锁
+func (as *AlertingService) dequeueFiring(alert *models.Alert) bool {
+	for key, group := range as.groups {
+		if group.event != notificationFiring {
+			continue
+		}
+		for i, queued := range group.alerts {
+			if queued != alert {
+				continue
+			}
+			group.alerts = append(group.alerts[:i], group.alerts[i+1:]...)
+			if len(group.alerts) == 0 {
+				delete(as.groups, key)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// flushGroups 发送等待时间已到的分组，发送前重新检查触发通知是否仍需发送，调用方需持有锁
+func (as *AlertingService) flushGroups(now time.Time) {
+	for key, group := range as.groups {
+		if now.Sub(group.started) < as.groupWait {
+			continue
+		}
+		delete(as.groups, key)
+
+		alerts := make([]*models.Alert, 0, len(group.alerts))
+		for _, alert := range group.alerts {
+			if group.event == notificationResolved || as.notifiable(alert) {
+				alerts = append(alerts, alert)
+			}
+		}
+
+		switch len(alerts) {
+		case 0:
+		case 1:
+			as.send(alerts[0], group.event)
+		default:
+			as.sendGroup(group, alerts)
+		}
+	}
+}
+
+// sendGroup 将一组告警作为一条通知发送，调用方需持有锁
+func (as *AlertingService) sendGroup(group *alertGroup, alerts []*models.Alert) {
+	values := make([]models.Alert, len(alerts))
+	for i, alert := range alerts {
+		values[i] = *alert
+	}
+	as.eventMgr.EmitAlertGroup(models.AlertGroup{Event: group.event, Labels: group.labels, Alerts: values})
+	log.Printf("Sending %d grouped %s alerts", len(alerts), group.event)
+
+	// 按渠道和目标去重，同一目标只收到一条分组通知，修复动作在告警触发时已执行。
+	// 解决通知发送给各告警收到过触发通知的渠道
+	var first *models.AlertRule
+	sent := make(map[string]bool)
+	for _, alert := range alerts {
+		rule, actions, ok := as.eventActions(alert, group.event)
+		if !ok {
+			continue
+		}
+		if first == nil {
+			first = &rule
+		}
+
+		for _, action := range actions {
+			if isRemediation(action.Type) {
+				continue
+			}
+			key := action.Type + "\x00" + action.Target
+			if as.notifier != nil && !sent[key] {
+				sent[key] = true
+				as.notifier.DispatchGroup(action, *first, values, group.event)
+			}
+		}
+	}
+
+	if group.event == notificationFiring {
+		for _, alert := range alerts {
+			as.recordNotified(alert, as.now())
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"system-monitor/backend/models"
+)
+
+// rulePackVersion 当前规则包格式版本
+const rulePackVersion = 1
+
+// 规则包格式
+const (
+	rulePackYAML = "yaml"
+	rulePackJSON = "json"
+)
+
+// 规则导入模式
+const (
+	importModeMerge   = "merge"   // 新增和更新包中的规则，保留包外的规则
+	importModeReplace = "replace" // 使本机规则与规则包完全一致，删除包外的规则
+)
+
+// ExportRules 将所有告警规则导出为 YAML 或 JSON 规则包，format 为空时使用 YAML
+func (as *AlertingService) ExportRules(format string) ([]byte, error) {
+	rules, err := as.GetRules()
+	if err != nil {
+		return nil, err
+	}
+
+	pack := models.RulePack{
+		Version:    rulePackVersion,
+		ExportedAt: time.Now(),
+		Rules:      rules,
+	}
+
+	switch strings.ToLower(format) {
+	case "", rulePackYAML, "yml":
+		return yaml.Marshal(pack)
+	case rulePackJSON:
+		return json.MarshalIndent(pack, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported rule pack format: %s", format)
+	}
+}
+
+// ImportRules 导入规则包，规则按名称与现有规则匹配。
+// 所有规则验证通过后才会修改，变更在一个事务中保存，dryRun 为 true 时只返回将要发生的变更。
+func (as *AlertingService) ImportRules(data []byte, format, mode string, dryRun bool) (models.RuleImportResult, error) {
+	switch mode {
+	case "":
+		mode = importModeMerge
+	case importModeMerge, importModeReplace:
+	default:
+		return models.RuleImportResult{}, fmt.Errorf("unknown import mode: %s", mode)
+	}
+
+	pack, err := parseRulePack(data, format)
+	if err != nil {
+		return models.RuleImportResult{}, err
+	}
+
+	// 验证和修改在同一次加锁中完成，导入期间规则不会被并发修改
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if err := as.validateRulePack(pack); err != nil {
+		return models.RuleImportResult{}, err
+	}
+
+	byName := make(map[string]models.AlertRule, len(as.rules))
+	for _, rule := range as.rules {
+		if _, ok := byName[rule.Name]; ok {
+			return models.RuleImportResult{}, fmt.Errorf("multiple existing rules are named %s, rename them before importing", rule.Name)
+		}
+		byName[rule.Name] = rule
+	}
+
+	result := models.RuleImportResult{
+		Created:   make([]string, 0),
+		Updated:   make([]models.RuleChange, 0),
+		Deleted:   make([]string, 0),
+		Unchanged: make([]string, 0),
+		DryRun:    dryRun,
+	}
+
+	now := time.Now()
+	var creates, updates []models.AlertRule
+	imported := make(map[string]bool, len(pack.Rules))
+	for _, rule := range pack.Rules {
+		imported[rule.Name] = true
+
+		current, ok := byName[rule.Name]
+		if !ok {
+			rule.CreatedAt = now
+			rule.UpdatedAt = now
+			creates = append(creates, rule)
+			result.Created = append(result.Created, rule.Name)
+			continue
+		}
+
+		fields := diffRules(current, rule)
+		if len(fields) == 0 {
+			result.Unchanged = append(result.Unchanged, rule.Name)
+			continue
+		}
+		rule.ID = current.ID
+		rule.CreatedAt = current.CreatedAt
+		rule.UpdatedAt = now
+		updates = append(updates, rule)
+		result.Updated = append(result.Updated, models.RuleChange{Name: rule.Name, Fields: fields})
+	}
+
+	var deletes []int64
+	if mode == importModeReplace {
+		for _, rule := range as.rules {
+			if !imported[rule.Name] {
+				deletes = append(deletes, rule.ID)
+				result.Deleted = append(result.Deleted, rule.Name)
+			}
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	// 所有变更在一个事务中保存，失败时规则保持导入前的状态
+	if as.storage != nil {
+		created := make([]*models.AlertRule, len(creates))
+		for i := range creates {
+			created[i] = &creates[i]
+		}
+		if err := as.storage.ApplyAlertRuleChanges(created, updates, deletes); err != nil {
+			return models.RuleImportResult{}, fmt.Errorf("failed to import rules: %w", err)
+		}
+	} else {
+		for i := range creates {
+			as.nextID++
+			creates[i].ID = as.nextID
+		}
+	}
+
+	as.rules = append(as.rules, creates...)
+	for _, rule := range updates {
+		as.replaceRule(as.ruleIndex(rule.ID), rule)
+	}
+	for _, id := range deletes {
+		as.removeRule(as.ruleIndex(id))
+	}
+
+	log.Printf("Imported alert rules (%s): %d created, %d updated, %d deleted, %d unchanged",
+		mode, len(result.Created), len(result.Updated), len(result.Deleted), len(result.Unchanged))
+	return result, nil
+}
+
+// parseRulePack 解析规则包，format 为空时根据内容判断格式，未知字段视为错误
+func parseRulePack(data []byte, format string) (models.RulePack, error) {
+	format = strings.ToLower(format)
+	if format == "" {
+		format = rulePackYAML
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			format = rulePackJSON
+		}
+	}
+
+	var pack models.RulePack
+	switch format {
+	case rulePackYAML, "yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&pack); err != nil {
+			return pack, fmt.Errorf("failed to parse YAML rule pack: %w", err)
+		}
+	case rulePackJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&pack); err != nil {
+			return pack, fmt.Errorf("failed to parse JSON rule pack: %w", err)
+		}
+	default:
+		return pack, fmt.Errorf("unsupported rule pack format: %s", format)
+	}
+
+	if pack.Version > rulePackVersion {
+		return pack, fmt.Errorf("rule pack version %d is newer than supported version %d", pack.Version, rulePackVersion)
+	}
+
+	// 规则ID和时间戳属于本机，不从规则包导入
+	for i := range pack.Rules {
+		pack.Rules[i].ID = 0
+		pack.Rules[i].CreatedAt = time.Time{}
+		pack.Rules[i].UpdatedAt = time.Time{}
+	}
+	return pack, nil
+}
+
+// validateRulePack 验证规则包中的所有规则，返回全部错误而不是第一个，调用方需持有锁
+func (as *AlertingService) validateRulePack(pack models.RulePack) error {
+	var errs []error
+	seen := make(map[string]bool, len(pack.Rules))
+	for i, rule := range pack.Rules {
+		if err := as.validateRule(rule); err != nil {
+			errs = append(errs, fmt.Errorf("rule %d (%s): %w", i+1, rule.Name, err))
+		}
+		if rule.Name != "" && seen[rule.Name] {
+			errs = append(errs, fmt.Errorf("rule %d (%s): duplicate rule name", i+1, rule.Name))
+		}
+		seen[rule.Name] = true
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid rule pack: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// diffRules 比较两条规则的定义，返回发生变化的字段（JSON 字段名），忽略ID和时间戳
+func diffRules(current, imported models.AlertRule) []string {
+	fields := make([]string, 0)
+	cv := reflect.ValueOf(current)
+	iv := reflect.ValueOf(imported)
+	t := cv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("yaml") == "-" {
+			continue
+		}
+
+		a, b := cv.Field(i), iv.Field(i)
+		// 空切片与 nil 等价
+		if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			fields = append(fields, strings.Split(field.Tag.Get("json"), ",")[0])
+		}
+	}
+	return fields
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"system-monitor/backend/models"
+)
+
+// testRulePack 构造包含 cpu（阈值 90）和 memory 两条规则的 YAML 规则包
+const testRulePack = `version: 1
+rules:
+  - name: cpu
+    metric: cpu
+    operator: ">"
+    threshold: 90
+    enabled: true
+    actions:
+      - type: notification
+        target: desktop
+        level: warning
+  - name: memory
+    metric: memory
+    operator: ">"
+    threshold: 80
+    enabled: true
+    actions:
+      - type: notification
+        target: desktop
+        level: warning
+`
+
+func createImportTestRules(t *testing.T, as *AlertingService) {
+	t.Helper()
+	for _, name := range []string{"cpu", "disk"} {
+		rule := models.AlertRule{
+			Name: name, Metric: name, Operator: ">", Threshold: 50, Enabled: true,
+			Actions: []models.AlertAction{{Type: "notification", Target: "desktop", Level: "warning"}},
+		}
+		if err := as.CreateRule(rule); err != nil {
+			t.Fatalf("failed to create rule %s: %v", name, err)
+		}
+	}
+}
+
+func ruleNames(rules []models.AlertRule) string {
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = rule.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestImportRulesReplace(t *testing.T) {
+	as, storage := newTestAlertingService(t)
+	createImportTestRules(t, as)
+
+	result, err := as.ImportRules([]byte(testRulePack), "", importModeReplace, false)
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if len(result.Created) != 1 || len(result.Updated) != 1 || len(result.Deleted) != 1 {
+		t.Errorf("unexpected import result: %+v", result)
+	}
+
+	rules, _ := as.GetRules()
+	stored, err := storage.GetAlertRules()
+	if err != nil {
+		t.Fatalf("failed to load rules: %v", err)
+	}
+	if got := ruleNames(rules); got != "cpu,memory" {
+		t.Errorf("unexpected rules after import: %s", got)
+	}
+	if got := ruleNames(stored); got != "cpu,memory" {
+		t.Errorf("unexpected stored rules after import: %s", got)
+	}
+	for i := range rules {
+		if rules[i].ID != stored[i].ID || rules[i].Threshold != stored[i].Threshold {
+			t.Errorf("rule %s differs from storage: %+v vs %+v", rules[i].Name, rules[i], stored[i])
+		}
+	}
+}
+
+func TestImportRulesRollsBackOnFailure(t *testing.T) {
+	as, storage := newTestAlertingService(t)
+	createImportTestRules(t, as)
+
+	// 规则 cpu 在数据库中已不存在，更新失败时整个导入回滚
+	if _, err := storage.db.Exec(`DELETE FROM alert_rules WHERE name = 'cpu'`); err != nil {
+		t.Fatalf("failed to delete rule: %v", err)
+	}
+	if _, err := as.ImportRules([]byte(testRulePack), "", importModeReplace, false); err == nil {
+		t.Fatal("expected the import to fail")
+	}
+
+	rules, _ := as.GetRules()
+	if got := ruleNames(rules); got != "cpu,disk" {
+		t.Errorf("rules changed after a failed import: %s", got)
+	}
+	if rules[0].Threshold != 50 {
+		t.Errorf("rule cpu was updated by a failed import: %v", rules[0].Threshold)
+	}
+	stored, err := storage.GetAlertRules()
+	if err != nil {
+		t.Fatalf("failed to load rules: %v", err)
+	}
+	if got := ruleNames(stored); got != "disk" {
+		t.Errorf("stored rules changed after a failed import: %s", got)
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"system-monitor/backend/models"
+)
+
+// levelPriority 告警级别的优先级，数值越大越严重
+var levelPriority = map[string]int{
+	"info":     1,
+	"warning":  2,
+	"critical": 3,
+}
+
+// validateInhibits 验证规则抑制的规则名称，被抑制的规则可以尚未创建
+func validateInhibits(rule models.AlertRule) error {
+	for _, name := range rule.Inhibits {
+		if name == "" {
+			return fmt.Errorf("inhibited rule name cannot be empty")
+		}
+		if name == rule.Name {
+			return fmt.Errorf("rule cannot inhibit itself")
+		}
+	}
+	return nil
+}
+
+// inhibits 检查规则是否抑制指定名称的规则
+func inhibits(rule models.AlertRule, name string) bool {
+	for _, inhibited := range rule.Inhibits {
+		if inhibited == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isInhibited 检查告警是否被其他规则级别更高的活动告警抑制，调用方需持有锁
+func (as *AlertingService) isInhibited(alert *models.Alert) bool {
+	for _, source := range as.active {
+		if source.RuleID == alert.RuleID || levelPriority[source.Level] <= levelPriority[alert.Level] {
+			continue
+		}
+		if rule, ok := as.findRule(source.RuleID); ok && inhibits(rule, alert.RuleName) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateInhibitions 重新计算活动告警的抑制状态，抑制解除后补发未发送的通知，调用方需持有锁
+func (as *AlertingService) updateInhibitions() {
+	for _, alert := range as.active {
+		inhibited := as.isInhibited(alert)
+		if inhibited == alert.Inhibited {
+			continue
+		}
+
+		alert.Inhibited = inhibited
+		if inhibited {
+			log.Printf("Alert %s inhibited by a higher level alert", alert.RuleName)
+			continue
+		}
+
+		// 被抑制前已发送过通知的告警不重复通知
+		log.Printf("Inhibition ended for alert %s, notifications resumed", alert.RuleName)
+		if alert.LastNotifiedAt == nil {
+			as.notify(alert)
+		}
+	}
+}
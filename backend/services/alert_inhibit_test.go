@@ -0,0 +1,76 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+func TestInhibitionIndependentOfRuleOrder(t *testing.T) {
+	warning := models.AlertRule{
+		Name:      "cpu-warning",
+		Metric:    "cpu",
+		Operator:  ">",
+		Threshold: 50,
+		Enabled:   true,
+		Actions:   []models.AlertAction{{Type: "webhook", Target: "ops", Level: "warning"}},
+	}
+	critical := models.AlertRule{
+		Name:      "cpu-critical",
+		Metric:    "cpu",
+		Operator:  ">",
+		Threshold: 80,
+		Enabled:   true,
+		Inhibits:  []string{"cpu-warning"},
+		Actions:   []models.AlertAction{{Type: "webhook", Target: "ops", Level: "critical"}},
+	}
+
+	cases := []struct {
+		name      string
+		rules     []models.AlertRule
+		groupWait time.Duration
+	}{
+		{"inhibited rule first", []models.AlertRule{warning, critical}, 0},
+		{"inhibiting rule first", []models.AlertRule{critical, warning}, 0},
+		{"inhibited rule first with grouping", []models.AlertRule{warning, critical}, time.Minute},
+		{"inhibiting rule first with grouping", []models.AlertRule{critical, warning}, time.Minute},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+			channel := &recordingChannel{}
+			ns := NewNotificationService(nil)
+			ns.RegisterChannel("webhook", channel)
+
+			as := NewAlertingService(nil, nil)
+			as.now = clock.Now
+			as.SetNotificationService(ns)
+			as.SetGrouping(nil, c.groupWait)
+			for _, rule := range c.rules {
+				if err := as.CreateRule(rule); err != nil {
+					t.Fatalf("failed to create rule %s: %v", rule.Name, err)
+				}
+			}
+
+			// 两条规则在同一次检查中触发，分组时等待时间到达后发送
+			for _, advance := range []time.Duration{0, c.groupWait} {
+				clock.Advance(advance)
+				if err := as.CheckAlerts(testMetrics(90)); err != nil {
+					t.Fatalf("CheckAlerts failed: %v", err)
+				}
+			}
+			ns.Wait()
+
+			sent := channel.snapshot()
+			if len(sent) != 1 || sent[0].level != "critical" {
+				t.Fatalf("expected only the critical notification, got %+v", sent)
+			}
+			for _, alert := range as.GetActiveAlerts() {
+				if alert.Inhibited != (alert.RuleName == "cpu-warning") {
+					t.Errorf("alert %s inhibited = %v", alert.RuleName, alert.Inhibited)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"system-monitor/backend/models"
+)
+
+// ruleTypeProcess 进程规则，在完整进程表上评估
+const ruleTypeProcess = "process"
+
+// 进程规则的条件
+const (
+	processAbsent  = "absent"  // 没有匹配的进程时触发
+	processPresent = "present" // 存在匹配的进程时触发
+	processCount   = "count"   // 匹配的进程数满足 Operator/Threshold 时触发
+	processCPU     = "cpu"     // 匹配进程在两次收集之间的 CPU 使用率满足条件时触发，每个进程独立告警
+	processRSS     = "rss"     // 匹配进程的常驻内存（MB）满足条件时触发，每个进程独立告警
+)
+
+// processMatcher 编译后的进程匹配条件
+type processMatcher struct {
+	name    string
+	cmdline *regexp.Regexp
+	user    string
+}
+
+// newProcessMatcher 编译进程匹配条件
+func newProcessMatcher(cfg models.ProcessConfig) (*processMatcher, error) {
+	m := &processMatcher{name: strings.ToLower(cfg.Name), user: cfg.User}
+	if _, err := path.Match(m.name, ""); err != nil {
+		return nil, fmt.Errorf("invalid process name pattern %q: %w", cfg.Name, err)
+	}
+	if _, err := path.Match(m.user, ""); err != nil {
+		return nil, fmt.Errorf("invalid user pattern %q: %w", cfg.User, err)
+	}
+	if cfg.Cmdline != "" {
+		re, err := regexp.Compile(cfg.Cmdline)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cmdline pattern: %w", err)
+		}
+		m.cmdline = re
+	}
+	return m, nil
+}
+
+// match 检查进程是否满足所有设置的条件
+func (m *processMatcher) match(proc models.ProcessInfo) bool {
+	if m.name != "" {
+		if matched, _ := path.Match(m.name, strings.ToLower(proc.Name)); !matched {
+			return false
+		}
+	}
+	if m.user != "" {
+		if matched, _ := path.Match(m.user, proc.Username); !matched {
+			return false
+		}
+	}
+	if m.cmdline != nil && !m.cmdline.MatchString(proc.Cmdline) {
+		return false
+	}
+	return true
+}
+
+// validateProcessRule 验证进程规则
+func validateProcessRule(rule models.AlertRule) error {
+	if rule.Expression != "" {
+		return fmt.Errorf("process rules cannot use an expression")
+	}
+	cfg := rule.Process
+	if cfg == nil {
+		return fmt.Errorf("process config is required")
+	}
+	if cfg.Name == "" && cfg.Cmdline == "" && cfg.User == "" {
+		return fmt.Errorf("at least one of process name, cmdline or user is required")
+	}
+	if _, err := newProcessMatcher(*cfg); err != nil {
+		return err
+	}
+
+	switch cfg.Condition {
+	case processAbsent, processPresent:
+		if rule.ClearThreshold != nil {
+			return fmt.Errorf("clear threshold is not supported for process condition %s", cfg.Condition)
+		}
+	case processCount, processCPU, processRSS:
+		if rule.Operator == "" {
+			return fmt.Errorf("operator cannot be empty")
+		}
+	default:
+		return fmt.Errorf("unknown process condition: %s", cfg.Condition)
+	}
+
+	return nil
+}
+
+// evaluateProcess 在完整进程表中匹配进程并评估规则条件
+func (e *ruleEvaluator) evaluateProcess(rule models.AlertRule, data map[string]interface{}) ([]alertInstance, error) {
+	table, ok := data["process_table"].([]models.ProcessInfo)
+	if !ok {
+		return nil, fmt.Errorf("process table not found")
+	}
+
+	m, err := newProcessMatcher(*rule.Process)
+	if err != nil {
+		return nil, err
+	}
+	var matched []models.ProcessInfo
+	for _, proc := range table {
+		if m.match(proc) {
+			matched = append(matched, proc)
+		}
+	}
+	count := float64(len(matched))
+
+	switch rule.Process.Condition {
+	case processAbsent:
+		instance := alertInstance{Value: count, Triggered: len(matched) == 0}
+		if instance.Triggered {
+			instance.Message = e.formatProcessMessage(rule, templateProcessAbsent, count)
+		}
+		return []alertInstance{instance}, nil
+	case processPresent:
+		instance := alertInstance{Value: count, Triggered: len(matched) > 0}
+		if instance.Triggered {
+			instance.Message = e.formatProcessMessage(rule, templateProcessPresent, count)
+		}
+		return []alertInstance{instance}, nil
+	case processCount:
+		return []alertInstance{{
+			Value:     count,
+			Triggered: e.as.evaluateCondition(count, rule.Operator, rule.Threshold),
+		}}, nil
+	}
+
+	// 每个进程以 名称[PID] 作为目标独立触发，进程退出后告警自动解决
+	samples := make([]metricSample, 0, len(matched))
+	for _, proc := range matched {
+		target := fmt.Sprintf("%s[%d]", proc.Name, proc.PID)
+		value := proc.CPUPercent
+		if rule.Process.Condition == processRSS {
+			value = float64(proc.MemRSS) / bytesPerMB
+		}
+		samples = append(samples, metricSample{Target: target, Names: []string{target, proc.Name}, Value: value})
+	}
+	return e.as.evaluateInstances(rule, samples), nil
+}
+
+// formatProcessMessage 格式化进程存在或缺失的告警消息
+func (e *ruleEvaluator) formatProcessMessage(rule models.AlertRule, name string, count float64) string {
+	data := newMessageData(rule, e.host, "", count, e.language)
+	return renderMessage(rule.MessageTemplate, e.language, name, data)
+}
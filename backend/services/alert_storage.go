@@ -0,0 +1,803 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// dbTimeLayout 与SQLite CURRENT_TIMESTAMP一致的时间格式（UTC）
+const dbTimeLayout = "2006-01-02 15:04:05"
+
+// dbTime 将时间转换为数据库存储格式
+func dbTime(t time.Time) string {
+	return t.UTC().Format(dbTimeLayout)
+}
+
+// initAlertTables 初始化告警规则相关的表
+func (s *StorageService) initAlertTables() error {
+	// 告警规则表，duration 以纳秒存储
+	if err := s.exec(`CREATE TABLE IF NOT EXISTS alert_rules (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		metric TEXT NOT NULL,
+		operator TEXT NOT NULL,
+		threshold REAL NOT NULL,
+		duration INTEGER NOT NULL DEFAULT 0,
+		enabled INTEGER NOT NULL DEFAULT 1,
+		actions TEXT NOT NULL DEFAULT '[]',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return err
+	}
+
+	// 应用元数据表
+	if err := s.exec(`CREATE TABLE IF NOT EXISTS app_meta (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	)`); err != nil {
+		return err
+	}
+
+	// 后续版本新增的列
+	columns := []struct{ table, name, definition string }{
+		{"alerts", "acknowledged_by", "TEXT NOT NULL DEFAULT ''"},
+		{"alerts", "acknowledged_at", "DATETIME"},
+		{"alerts", "ack_comment", "TEXT NOT NULL DEFAULT ''"},
+		{"alerts", "target", "TEXT NOT NULL DEFAULT ''"},
+		{"alerts", "suppressed", "INTEGER NOT NULL DEFAULT 0"},
+		{"alerts", "escalation", "INTEGER NOT NULL DEFAULT 0"},
+		{"alerts", "last_notified_at", "DATETIME"},
+		{"alerts", "remediated", "INTEGER NOT NULL DEFAULT 0"},
+		{"alert_rules", "target", "TEXT NOT NULL DEFAULT ''"},
+		{"alert_rules", "expression", "TEXT NOT NULL DEFAULT ''"},
+		{"alert_rules", "type", "TEXT NOT NULL DEFAULT ''"},
+		{"alert_rules", "anomaly", "TEXT NOT NULL DEFAULT ''"},
+		{"alert_rules", "message_template", "TEXT NOT NULL DEFAULT ''"},
+		{"alert_rules", "clear_threshold", "REAL"},
+		{"alert_rules", "flap", "TEXT NOT NULL DEFAULT ''"},
+		{"alert_rules", "escalation", "TEXT NOT NULL DEFAULT ''"},
+		{"alert_rules", "inhibits", "TEXT NOT NULL DEFAULT ''"},
+		{"alert_rules", "process", "TEXT NOT NULL DEFAULT ''"},
+	}
+	for _, col := range columns {
+		if err := s.addColumnIfMissing(col.table, col.name, col.definition); err != nil {
+			return err
+		}
+	}
+
+	// 维护窗口表
+	if err := s.exec(`CREATE TABLE IF NOT EXISTS maintenance_windows (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		schedule TEXT NOT NULL,
+		duration INTEGER NOT NULL,
+		timezone TEXT NOT NULL DEFAULT '',
+		rule_ids TEXT NOT NULL DEFAULT '[]',
+		enabled BOOLEAN NOT NULL DEFAULT 1,
+		comment TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return err
+	}
+
+	// 通知投递记录表
+	if err := s.exec(`CREATE TABLE IF NOT EXISTS notification_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		alert_id INTEGER NOT NULL,
+		rule_id INTEGER NOT NULL,
+		channel TEXT NOT NULL,
+		target TEXT NOT NULL DEFAULT '',
+		event TEXT NOT NULL,
+		status TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return err
+	}
+
+	// 修复动作执行记录表，duration 以纳秒存储
+	if err := s.exec(`CREATE TABLE IF NOT EXISTS remediation_executions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		alert_id INTEGER NOT NULL,
+		rule_id INTEGER NOT NULL,
+		action TEXT NOT NULL,
+		target TEXT NOT NULL DEFAULT '',
+		pid INTEGER NOT NULL DEFAULT 0,
+		status TEXT NOT NULL,
+		exit_code INTEGER NOT NULL DEFAULT 0,
+		output TEXT NOT NULL DEFAULT '',
+		error TEXT NOT NULL DEFAULT '',
+		duration INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return err
+	}
+
+	// 告警静默表，target 为空时静默规则的所有目标
+	if err := s.exec(`CREATE TABLE IF NOT EXISTS alert_silences (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		rule_id INTEGER NOT NULL,
+		target TEXT NOT NULL DEFAULT '',
+		alert_id INTEGER NOT NULL DEFAULT 0,
+		created_by TEXT NOT NULL DEFAULT '',
+		comment TEXT NOT NULL DEFAULT '',
+		starts_at DATETIME NOT NULL,
+		ends_at DATETIME NOT NULL,
+		expired INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return err
+	}
+
+	// 早期版本创建的静默表没有目标列
+	return s.addColumnIfMissing("alert_silences", "target", "TEXT NOT NULL DEFAULT ''")
+}
+
+// addColumnIfMissing 为已存在的表补充新增的列
+func (s *StorageService) addColumnIfMissing(table, column, definition string) error {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+
+	exists := false
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		if name == column {
+			exists = true
+		}
+	}
+	rows.Close()
+
+	if exists {
+		return nil
+	}
+
+	return s.exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+}
+
+// GetMeta 读取应用元数据
+func (s *StorageService) GetMeta(key string) (string, bool, error) {
+	var value string
+	err := s.db.QueryRow("SELECT value FROM app_meta WHERE key = ?", key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// SetMeta 写入应用元数据
+func (s *StorageService) SetMeta(key, value string) error {
+	return s.exec("INSERT OR REPLACE INTO app_meta (key, value) VALUES (?, ?)", key, value)
+}
+
+// alertRuleColumns 告警规则表中可更新的列，顺序与 alertRuleValues 一致
+var alertRuleColumns = []string{
+	"name", "metric", "target", "operator", "threshold", "clear_threshold", "expression", "type", "anomaly",
+	"process", "flap", "escalation", "inhibits", "message_template", "duration", "enabled", "actions", "updated_at",
+}
+
+// alertRuleValues 将告警规则编码为 alertRuleColumns 对应的列值，duration 以纳秒存储
+func alertRuleValues(rule models.AlertRule) ([]interface{}, error) {
+	actions, err := json.Marshal(rule.Actions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode actions: %w", err)
+	}
+
+	anomaly, err := encodeOptionalJSON(rule.Anomaly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode anomaly config: %w", err)
+	}
+
+	process, err := encodeOptionalJSON(rule.Process)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode process config: %w", err)
+	}
+
+	flap, err := encodeOptionalJSON(rule.Flap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode flap config: %w", err)
+	}
+
+	escalation, err := encodeOptionalJSON(rule.Escalation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode escalation policy: %w", err)
+	}
+
+	inhibits, err := encodeOptionalJSON(rule.Inhibits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode inhibited rules: %w", err)
+	}
+
+	var clearThreshold interface{}
+	if rule.ClearThreshold != nil {
+		clearThreshold = *rule.ClearThreshold
+	}
+
+	return []interface{}{
+		rule.Name, rule.Metric, rule.Target, rule.Operator, rule.Threshold, clearThreshold, rule.Expression, rule.Type,
+		anomaly, process, flap, escalation, inhibits, rule.MessageTemplate, int64(rule.Duration), rule.Enabled, string(actions),
+		dbTime(rule.UpdatedAt),
+	}, nil
+}
+
+// encodeOptionalJSON 编码可选配置（指针类型），nil 编码为空字符串
+func encodeOptionalJSON(v interface{}) (string, error) {
+	if v == nil || reflect.ValueOf(v).IsNil() {
+		return "", nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// decodeOptionalJSON 解码可选配置，空字符串保持 nil
+func decodeOptionalJSON(data string, v interface{}) error {
+	if data == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(data), v)
+}
+
+// GetAlertRules 获取所有告警规则
+func (s *StorageService) GetAlertRules() ([]models.AlertRule, error) {
+	rows, err := s.db.Query(fmt.Sprintf("SELECT id, %s, created_at FROM alert_rules ORDER BY id ASC",
+		strings.Join(alertRuleColumns, ", ")))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]models.AlertRule, 0)
+	for rows.Next() {
+		var rule models.AlertRule
+		var duration int64
+		var actions, anomaly, process, flap, escalation, inhibits string
+		var clearThreshold sql.NullFloat64
+		var createdAt, updatedAt sql.NullTime
+
+		if err := rows.Scan(&rule.ID, &rule.Name, &rule.Metric, &rule.Target, &rule.Operator, &rule.Threshold,
+			&clearThreshold, &rule.Expression, &rule.Type, &anomaly, &process, &flap,
+			&escalation, &inhibits, &rule.MessageTemplate, &duration, &rule.Enabled, &actions, &updatedAt, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule: %w", err)
+		}
+
+		rule.Duration = time.Duration(duration)
+		rule.CreatedAt = createdAt.Time
+		rule.UpdatedAt = updatedAt.Time
+		if err := json.Unmarshal([]byte(actions), &rule.Actions); err != nil {
+			return nil, fmt.Errorf("failed to decode actions of rule %d: %w", rule.ID, err)
+		}
+		if err := decodeOptionalJSON(anomaly, &rule.Anomaly); err != nil {
+			return nil, fmt.Errorf("failed to decode anomaly config of rule %d: %w", rule.ID, err)
+		}
+		if err := decodeOptionalJSON(process, &rule.Process); err != nil {
+			return nil, fmt.Errorf("failed to decode process config of rule %d: %w", rule.ID, err)
+		}
+		if err := decodeOptionalJSON(flap, &rule.Flap); err != nil {
+			return nil, fmt.Errorf("failed to decode flap config of rule %d: %w", rule.ID, err)
+		}
+		if err := decodeOptionalJSON(escalation, &rule.Escalation); err != nil {
+			return nil, fmt.Errorf("failed to decode escalation policy of rule %d: %w", rule.ID, err)
+		}
+		if err := decodeOptionalJSON(inhibits, &rule.Inhibits); err != nil {
+			return nil, fmt.Errorf("failed to decode inhibited rules of rule %d: %w", rule.ID, err)
+		}
+		if clearThreshold.Valid {
+			rule.ClearThreshold = &clearThreshold.Float64
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// sqlExecer *sql.DB 和 *sql.Tx 共有的执行方法，规则的增删改可以在事务中执行
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// CreateAlertRule 保存新的告警规则，并回填数据库分配的ID
+func (s *StorageService) CreateAlertRule(rule *models.AlertRule) error {
+	return createAlertRule(s.db, rule)
+}
+
+// UpdateAlertRule 更新告警规则
+func (s *StorageService) UpdateAlertRule(rule models.AlertRule) error {
+	return updateAlertRule(s.db, rule)
+}
+
+// DeleteAlertRule 删除告警规则
+func (s *StorageService) DeleteAlertRule(id int64) error {
+	return s.exec("DELETE FROM alert_rules WHERE id = ?", id)
+}
+
+// ApplyAlertRuleChanges 在同一事务中新增、更新和删除告警规则，任一操作失败时全部回滚。
+// 新增的规则回填数据库分配的ID
+func (s *StorageService) ApplyAlertRuleChanges(creates []*models.AlertRule, updates []models.AlertRule, deletes []int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range creates {
+		if err := createAlertRule(tx, rule); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create rule %s: %w", rule.Name, err)
+		}
+	}
+	for _, rule := range updates {
+		if err := updateAlertRule(tx, rule); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update rule %s: %w", rule.Name, err)
+		}
+	}
+	for _, id := range deletes {
+		if _, err := tx.Exec("DELETE FROM alert_rules WHERE id = ?", id); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to delete rule %d: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// createAlertRule 插入告警规则并回填ID
+func createAlertRule(db sqlExecer, rule *models.AlertRule) error {
+	values, err := alertRuleValues(*rule)
+	if err != nil {
+		return err
+	}
+
+	columns := append(append([]string{}, alertRuleColumns...), "created_at")
+	values = append(values, dbTime(rule.CreatedAt))
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+
+	result, err := db.Exec(fmt.Sprintf("INSERT INTO alert_rules (%s) VALUES (%s)",
+		strings.Join(columns, ", "), placeholders), values...)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	rule.ID = id
+
+	return nil
+}
+
+// updateAlertRule 按ID更新告警规则，规则不存在时返回错误
+func updateAlertRule(db sqlExecer, rule models.AlertRule) error {
+	values, err := alertRuleValues(rule)
+	if err != nil {
+		return err
+	}
+
+	assignments := make([]string, len(alertRuleColumns))
+	for i, column := range alertRuleColumns {
+		assignments[i] = column + " = ?"
+	}
+
+	result, err := db.Exec(fmt.Sprintf("UPDATE alert_rules SET %s WHERE id = ?",
+		strings.Join(assignments, ", ")), append(values, rule.ID)...)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("rule with ID %d not found", rule.ID)
+	}
+
+	return nil
+}
+
+// InsertAlert 记录触发的告警，并回填数据库分配的ID
+func (s *StorageService) InsertAlert(alert *models.Alert) error {
+	result, err := s.db.Exec(`INSERT INTO alerts (rule_id, rule_name, target, message, level, value, threshold, status,
+		suppressed, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		alert.RuleID, alert.RuleName, alert.Target, alert.Message, alert.Level, alert.Value, alert.Threshold,
+		alert.Status, alert.Suppressed, dbTime(alert.CreatedAt))
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	alert.ID = id
+
+	return nil
+}
+
+// ResolveAlert 更新告警的状态和解决时间
+func (s *StorageService) ResolveAlert(alert *models.Alert) error {
+	var resolvedAt interface{}
+	if alert.ResolvedAt != nil {
+		resolvedAt = dbTime(*alert.ResolvedAt)
+	}
+
+	return s.exec("UPDATE alerts SET status = ?, resolved_at = ? WHERE id = ?",
+		alert.Status, resolvedAt, alert.ID)
+}
+
+// QueryAlerts 按条件查询告警记录，按触发时间倒序排列
+func (s *StorageService) QueryAlerts(query models.AlertQuery) ([]models.Alert, error) {
+	sqlQuery := `SELECT id, rule_id, rule_name, target, message, level, value, threshold, status, created_at, resolved_at,
+		acknowledged_by, acknowledged_at, ack_comment, suppressed, escalation, last_notified_at, remediated
+		FROM alerts WHERE 1 = 1`
+	var args []interface{}
+
+	if query.Level != "" {
+		sqlQuery += " AND level = ?"
+		args = append(args, query.Level)
+	}
+	if query.RuleID != 0 {
+		sqlQuery += " AND rule_id = ?"
+		args = append(args, query.RuleID)
+	}
+	if query.Status != "" {
+		sqlQuery += " AND status = ?"
+		args = append(args, query.Status)
+	}
+	if query.Since != nil {
+		sqlQuery += " AND created_at >= ?"
+		args = append(args, dbTime(*query.Since))
+	}
+	if query.Until != nil {
+		sqlQuery += " AND created_at <= ?"
+		args = append(args, dbTime(*query.Until))
+	}
+
+	sqlQuery += " ORDER BY created_at DESC, id DESC"
+	if query.Limit > 0 {
+		sqlQuery += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := s.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := make([]models.Alert, 0)
+	for rows.Next() {
+		var alert models.Alert
+		var createdAt, resolvedAt, acknowledgedAt, lastNotifiedAt sql.NullTime
+
+		if err := rows.Scan(&alert.ID, &alert.RuleID, &alert.RuleName, &alert.Target, &alert.Message, &alert.Level,
+			&alert.Value, &alert.Threshold, &alert.Status, &createdAt, &resolvedAt,
+			&alert.AcknowledgedBy, &acknowledgedAt, &alert.AckComment, &alert.Suppressed,
+			&alert.Escalation, &lastNotifiedAt, &alert.Remediated); err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+
+		alert.CreatedAt = createdAt.Time
+		if resolvedAt.Valid {
+			t := resolvedAt.Time
+			alert.ResolvedAt = &t
+		}
+		if acknowledgedAt.Valid {
+			t := acknowledgedAt.Time
+			alert.AcknowledgedAt = &t
+		}
+		if lastNotifiedAt.Valid {
+			t := lastNotifiedAt.Time
+			alert.LastNotifiedAt = &t
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+// AcknowledgeAlert 记录告警确认信息
+func (s *StorageService) AcknowledgeAlert(alert *models.Alert) error {
+	var acknowledgedAt interface{}
+	if alert.AcknowledgedAt != nil {
+		acknowledgedAt = dbTime(*alert.AcknowledgedAt)
+	}
+
+	return s.exec("UPDATE alerts SET acknowledged_by = ?, acknowledged_at = ?, ack_comment = ? WHERE id = ?",
+		alert.AcknowledgedBy, acknowledgedAt, alert.AckComment, alert.ID)
+}
+
+// UpdateAlertEscalation 记录告警的级别、升级步骤和最近一次通知时间
+func (s *StorageService) UpdateAlertEscalation(alert *models.Alert) error {
+	var lastNotifiedAt interface{}
+	if alert.LastNotifiedAt != nil {
+		lastNotifiedAt = dbTime(*alert.LastNotifiedAt)
+	}
+
+	return s.exec("UPDATE alerts SET level = ?, escalation = ?, last_notified_at = ? WHERE id = ?",
+		alert.Level, alert.Escalation, lastNotifiedAt, alert.ID)
+}
+
+// UpdateAlertSuppressed 记录告警是否处于维护窗口内
+func (s *StorageService) UpdateAlertSuppressed(alert *models.Alert) error {
+	return s.exec("UPDATE alerts SET suppressed = ? WHERE id = ?", alert.Suppressed, alert.ID)
+}
+
+// UpdateAlertRemediated 记录告警已执行修复动作
+func (s *StorageService) UpdateAlertRemediated(alert *models.Alert) error {
+	return s.exec("UPDATE alerts SET remediated = ? WHERE id = ?", alert.Remediated, alert.ID)
+}
+
+// GetSilences 获取告警静默，activeOnly 为 true 时只返回未过期的静默
+func (s *StorageService) GetSilences(activeOnly bool) ([]models.AlertSilence, error) {
+	query := `SELECT id, rule_id, target, alert_id, created_by, comment, starts_at, ends_at, expired, created_at
+		FROM alert_silences`
+	if activeOnly {
+		query += " WHERE expired = 0"
+	}
+	query += " ORDER BY id ASC"
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	silences := make([]models.AlertSilence, 0)
+	for rows.Next() {
+		var silence models.AlertSilence
+		var startsAt, endsAt, createdAt sql.NullTime
+
+		if err := rows.Scan(&silence.ID, &silence.RuleID, &silence.Target, &silence.AlertID, &silence.CreatedBy, &silence.Comment,
+			&startsAt, &endsAt, &silence.Expired, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan silence: %w", err)
+		}
+
+		silence.StartsAt = startsAt.Time
+		silence.EndsAt = endsAt.Time
+		silence.CreatedAt = createdAt.Time
+		silences = append(silences, silence)
+	}
+
+	return silences, rows.Err()
+}
+
+// CreateSilence 保存告警静默，并回填数据库分配的ID
+func (s *StorageService) CreateSilence(silence *models.AlertSilence) error {
+	result, err := s.db.Exec(`INSERT INTO alert_silences (rule_id, target, alert_id, created_by, comment, starts_at, ends_at, expired, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		silence.RuleID, silence.Target, silence.AlertID, silence.CreatedBy, silence.Comment,
+		dbTime(silence.StartsAt), dbTime(silence.EndsAt), silence.Expired, dbTime(silence.CreatedAt))
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	silence.ID = id
+
+	return nil
+}
+
+// ExpireSilence 将告警静默标记为已过期
+func (s *StorageService) ExpireSilence(silence models.AlertSilence) error {
+	return s.exec("UPDATE alert_silences SET expired = 1, ends_at = ? WHERE id = ?",
+		dbTime(silence.EndsAt), silence.ID)
+}
+
+// GetMaintenanceWindows 获取所有维护窗口
+func (s *StorageService) GetMaintenanceWindows() ([]models.MaintenanceWindow, error) {
+	rows, err := s.db.Query(`SELECT id, name, schedule, duration, timezone, rule_ids, enabled, comment, created_at, updated_at
+		FROM maintenance_windows ORDER BY id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	windows := make([]models.MaintenanceWindow, 0)
+	for rows.Next() {
+		var window models.MaintenanceWindow
+		var duration int64
+		var ruleIDs string
+		var createdAt, updatedAt sql.NullTime
+
+		if err := rows.Scan(&window.ID, &window.Name, &window.Schedule, &duration, &window.Timezone, &ruleIDs,
+			&window.Enabled, &window.Comment, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+
+		window.Duration = time.Duration(duration)
+		window.CreatedAt = createdAt.Time
+		window.UpdatedAt = updatedAt.Time
+		if err := json.Unmarshal([]byte(ruleIDs), &window.RuleIDs); err != nil {
+			return nil, fmt.Errorf("failed to decode rules of maintenance window %d: %w", window.ID, err)
+		}
+
+		windows = append(windows, window)
+	}
+
+	return windows, rows.Err()
+}
+
+// CreateMaintenanceWindow 保存维护窗口，并回填数据库分配的ID
+func (s *StorageService) CreateMaintenanceWindow(window *models.MaintenanceWindow) error {
+	ruleIDs, err := encodeRuleIDs(window.RuleIDs)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(`INSERT INTO maintenance_windows (name, schedule, duration, timezone, rule_ids, enabled, comment,
+		created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		window.Name, window.Schedule, int64(window.Duration), window.Timezone, ruleIDs, window.Enabled, window.Comment,
+		dbTime(window.CreatedAt), dbTime(window.UpdatedAt))
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	window.ID = id
+
+	return nil
+}
+
+// UpdateMaintenanceWindow 更新维护窗口
+func (s *StorageService) UpdateMaintenanceWindow(window models.MaintenanceWindow) error {
+	ruleIDs, err := encodeRuleIDs(window.RuleIDs)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(`UPDATE maintenance_windows SET name = ?, schedule = ?, duration = ?, timezone = ?, rule_ids = ?,
+		enabled = ?, comment = ?, updated_at = ? WHERE id = ?`,
+		window.Name, window.Schedule, int64(window.Duration), window.Timezone, ruleIDs, window.Enabled, window.Comment,
+		dbTime(window.UpdatedAt), window.ID)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("maintenance window with ID %d not found", window.ID)
+	}
+
+	return nil
+}
+
+// DeleteMaintenanceWindow 删除维护窗口
+func (s *StorageService) DeleteMaintenanceWindow(id int64) error {
+	return s.exec("DELETE FROM maintenance_windows WHERE id = ?", id)
+}
+
+// encodeRuleIDs 将规则ID列表编码为JSON，nil 编码为空数组
+func encodeRuleIDs(ruleIDs []int64) (string, error) {
+	if ruleIDs == nil {
+		ruleIDs = []int64{}
+	}
+	data, err := json.Marshal(ruleIDs)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode rule IDs: %w", err)
+	}
+	return string(data), nil
+}
+
+// InsertDelivery 记录通知投递结果，并回填数据库分配的ID
+func (s *StorageService) InsertDelivery(delivery *models.NotificationDelivery) error {
+	result, err := s.db.Exec(`INSERT INTO notification_deliveries (alert_id, rule_id, channel, target, event, status, attempts, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		delivery.AlertID, delivery.RuleID, delivery.Channel, delivery.Target, delivery.Event,
+		delivery.Status, delivery.Attempts, delivery.Error, dbTime(delivery.CreatedAt))
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	delivery.ID = id
+
+	return nil
+}
+
+// GetDeliveries 获取最近的通知投递记录，按时间倒序排列
+func (s *StorageService) GetDeliveries(limit int) ([]models.NotificationDelivery, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.Query(`SELECT id, alert_id, rule_id, channel, target, event, status, attempts, error, created_at
+		FROM notification_deliveries ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.NotificationDelivery, 0)
+	for rows.Next() {
+		var delivery models.NotificationDelivery
+		var createdAt sql.NullTime
+
+		if err := rows.Scan(&delivery.ID, &delivery.AlertID, &delivery.RuleID, &delivery.Channel, &delivery.Target,
+			&delivery.Event, &delivery.Status, &delivery.Attempts, &delivery.Error, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification delivery: %w", err)
+		}
+
+		delivery.CreatedAt = createdAt.Time
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// InsertRemediation 记录修复动作执行结果，并回填数据库分配的ID
+func (s *StorageService) InsertRemediation(execution *models.RemediationExecution) error {
+	result, err := s.db.Exec(`INSERT INTO remediation_executions (alert_id, rule_id, action, target, pid, status, exit_code, output, error, duration, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		execution.AlertID, execution.RuleID, execution.Action, execution.Target, execution.PID, execution.Status,
+		execution.ExitCode, execution.Output, execution.Error, int64(execution.Duration), dbTime(execution.CreatedAt))
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	execution.ID = id
+
+	return nil
+}
+
+// GetRemediations 获取最近的修复动作执行记录，按时间倒序排列
+func (s *StorageService) GetRemediations(limit int) ([]models.RemediationExecution, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.Query(`SELECT id, alert_id, rule_id, action, target, pid, status, exit_code, output, error, duration, created_at
+		FROM remediation_executions ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	executions := make([]models.RemediationExecution, 0)
+	for rows.Next() {
+		var execution models.RemediationExecution
+		var duration int64
+		var createdAt sql.NullTime
+
+		if err := rows.Scan(&execution.ID, &execution.AlertID, &execution.RuleID, &execution.Action, &execution.Target,
+			&execution.PID, &execution.Status, &execution.ExitCode, &execution.Output, &execution.Error,
+			&duration, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan remediation execution: %w", err)
+		}
+
+		execution.Duration = time.Duration(duration)
+		execution.CreatedAt = createdAt.Time
+		executions = append(executions, execution)
+	}
+
+	return executions, rows.Err()
+}
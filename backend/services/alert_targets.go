@@ -0,0 +1,240 @@
+package services
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"system-monitor/backend/models"
+)
+
+// 聚合所有目标的选择器
+const (
+	targetAny = "any" // 任一目标满足条件即触发，只产生一个告警
+	targetAll = "all" // 所有目标都满足条件才触发，只产生一个告警
+)
+
+// bytesPerMB 字节速率指标以 MB/s 参与告警评估，与 AlertsConfig.NetworkThreshold 的单位一致
+const bytesPerMB = 1024 * 1024
+
+// readOnlyFilesystems 始终写满的只读文件系统，如 snap 包和光盘镜像，不参与磁盘告警评估
+var readOnlyFilesystems = map[string]bool{
+	"squashfs": true,
+	"iso9660":  true,
+	"udf":      true,
+	"erofs":    true,
+}
+
+// alertableDisk 检查挂载点是否参与磁盘告警评估，只读挂载的文件系统无法写满，也不参与评估
+func alertableDisk(disk models.DiskInfo) bool {
+	if readOnlyFilesystems[disk.Fstype] {
+		return false
+	}
+	for _, opt := range strings.Split(disk.Opts, ",") {
+		if opt == "ro" {
+			return false
+		}
+	}
+	return true
+}
+
+// metricSample 指标在单个目标上的取值
+type metricSample struct {
+	Target string   // 目标名称，单一序列的指标为空
+	Names  []string // 可用于匹配选择器的名称
+	Value  float64
+}
+
+// alertInstance 规则在单个目标上的评估结果
+type alertInstance struct {
+	Target    string
+	Value     float64
+	Triggered bool
+	Message   string // 为空时使用默认格式的告警消息
+}
+
+// alertKey 告警实例的唯一键，同一规则的不同目标独立触发和解决
+func alertKey(ruleID int64, target string) string {
+	return fmt.Sprintf("%d/%s", ruleID, target)
+}
+
+// getMetricSamples 获取指标在各目标上的取值
+func getMetricSamples(metric string, data map[string]interface{}) ([]metricSample, error) {
+	switch metric {
+	case "cpu":
+		if cpuData, ok := data["cpu"]; ok {
+			if cpuInfo, ok := cpuData.(*models.CPUInfo); ok {
+				return []metricSample{{Value: cpuInfo.Usage}}, nil
+			}
+		}
+	case "memory":
+		if memData, ok := data["memory"]; ok {
+			if memInfo, ok := memData.(*models.MemoryInfo); ok {
+				return []metricSample{{Value: memInfo.UsedPercent}}, nil
+			}
+		}
+	case "disk":
+		if diskData, ok := data["disk"]; ok {
+			if disks, ok := diskData.([]models.DiskInfo); ok {
+				samples := make([]metricSample, 0, len(disks))
+				for _, disk := range disks {
+					if !alertableDisk(disk) {
+						continue
+					}
+					samples = append(samples, metricSample{
+						Target: disk.Mountpoint,
+						Names:  []string{disk.Mountpoint, disk.Device, filepath.Base(disk.Device)},
+						Value:  disk.UsedPercent,
+					})
+				}
+				return samples, nil
+			}
+		}
+	case "network", "network_errors", "network.rx_rate", "network.tx_rate", "network.errors_rate":
+		// 采集数据中的流量和错误数是累计值，告警按相邻两次采样计算的速率评估
+		if rateData, ok := data["network_rates"]; ok {
+			if rates, ok := rateData.([]models.NetworkRate); ok {
+				samples := make([]metricSample, 0, len(rates))
+				for _, rate := range rates {
+					var value float64
+					switch metric {
+					case "network":
+						value = (rate.RxRate + rate.TxRate) / bytesPerMB
+					case "network.rx_rate":
+						value = rate.RxRate / bytesPerMB
+					case "network.tx_rate":
+						value = rate.TxRate / bytesPerMB
+					case "network_errors", "network.errors_rate":
+						value = rate.ErrorsRate
+					}
+					samples = append(samples, metricSample{
+						Target: rate.Interface,
+						Names:  []string{rate.Interface},
+						Value:  value,
+					})
+				}
+				return samples, nil
+			}
+		}
+	case "disk.read_rate", "disk.write_rate", "disk.iops":
+		if rateData, ok := data["disk_rates"]; ok {
+			if rates, ok := rateData.([]models.DiskIORate); ok {
+				samples := make([]metricSample, 0, len(rates))
+				for _, rate := range rates {
+					var value float64
+					switch metric {
+					case "disk.read_rate":
+						value = rate.ReadRate / bytesPerMB
+					case "disk.write_rate":
+						value = rate.WriteRate / bytesPerMB
+					case "disk.iops":
+						value = rate.IOPS
+					}
+					samples = append(samples, metricSample{
+						Target: rate.Mountpoint,
+						Names:  []string{rate.Mountpoint, rate.Device, filepath.Base(rate.Device)},
+						Value:  value,
+					})
+				}
+				return samples, nil
+			}
+		}
+	case "processes":
+		// 优先使用完整进程表，"processes" 只包含资源使用最高的进程
+		if procData, ok := data["process_table"]; ok {
+			if procs, ok := procData.([]models.ProcessInfo); ok {
+				return []metricSample{{Value: float64(len(procs))}}, nil
+			}
+		}
+		if procData, ok := data["processes"]; ok {
+			if procs, ok := procData.([]models.ProcessInfo); ok {
+				return []metricSample{{Value: float64(len(procs))}}, nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown metric: %s", metric)
+	}
+
+	return nil, fmt.Errorf("metric data not found: %s", metric)
+}
+
+// matchTarget 检查目标名称是否匹配选择器，支持通配符
+func matchTarget(selector string, names []string) bool {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if name == selector {
+			return true
+		}
+		if matched, err := path.Match(selector, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateInstances 按规则的目标选择器评估各目标，返回需要跟踪的告警实例
+func (as *AlertingService) evaluateInstances(rule models.AlertRule, samples []metricSample) []alertInstance {
+	selector := strings.TrimSpace(rule.Target)
+
+	// 单一序列的指标忽略目标选择器
+	if len(samples) == 1 && samples[0].Target == "" {
+		return []alertInstance{{
+			Value:     samples[0].Value,
+			Triggered: as.evaluateCondition(samples[0].Value, rule.Operator, rule.Threshold),
+		}}
+	}
+
+	switch selector {
+	case targetAny, targetAll:
+		if len(samples) == 0 {
+			return []alertInstance{{Target: selector}}
+		}
+
+		// 聚合告警的取值为首个满足条件的目标，都不满足时取第一个目标
+		instance := alertInstance{Target: selector, Value: samples[0].Value, Triggered: selector == targetAll}
+		found := false
+		for _, sample := range samples {
+			triggered := as.evaluateCondition(sample.Value, rule.Operator, rule.Threshold)
+			if triggered && !found {
+				instance.Value = sample.Value
+				found = true
+			}
+			if selector == targetAny && triggered {
+				instance.Triggered = true
+			}
+			if selector == targetAll && !triggered {
+				instance.Triggered = false
+			}
+		}
+		return []alertInstance{instance}
+	}
+
+	// 为空时评估所有目标，否则只评估匹配的目标
+	instances := make([]alertInstance, 0, len(samples))
+	for _, sample := range samples {
+		if selector != "" && selector != "*" && !matchTarget(selector, sample.Names) {
+			continue
+		}
+		instances = append(instances, alertInstance{
+			Target:    sample.Target,
+			Value:     sample.Value,
+			Triggered: as.evaluateCondition(sample.Value, rule.Operator, rule.Threshold),
+		})
+	}
+	return instances
+}
+
+// validateTarget 验证目标选择器
+func validateTarget(selector string) error {
+	selector = strings.TrimSpace(selector)
+	if selector == "" || selector == targetAny || selector == targetAll {
+		return nil
+	}
+	if _, err := path.Match(selector, ""); err != nil {
+		return fmt.Errorf("invalid target pattern %q: %w", selector, err)
+	}
+	return nil
+}
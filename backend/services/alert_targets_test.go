@@ -0,0 +1,167 @@
+package services
+
+import (
+	"testing"
+
+	"system-monitor/backend/models"
+)
+
+func TestDiskSamplesSkipReadOnlyFilesystems(t *testing.T) {
+	disks := []models.DiskInfo{
+		{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4", Opts: "rw,relatime", UsedPercent: 70},
+		{Device: "/dev/loop3", Mountpoint: "/snap/core/1", Fstype: "squashfs", Opts: "ro,nodev", UsedPercent: 100},
+		{Device: "/dev/sr0", Mountpoint: "/media/cdrom", Fstype: "iso9660", Opts: "ro", UsedPercent: 100},
+		{Device: "/dev/sdb1", Mountpoint: "/mnt/backup", Fstype: "ext4", Opts: "ro,relatime", UsedPercent: 100},
+		{Device: "C:", Mountpoint: "C:", Fstype: "NTFS", Opts: "rw.compress", UsedPercent: 80},
+	}
+	data := map[string]interface{}{"disk": disks}
+
+	samples, err := getMetricSamples("disk", data)
+	if err != nil {
+		t.Fatalf("failed to get disk samples: %v", err)
+	}
+	if len(samples) != 2 || samples[0].Target != "/" || samples[1].Target != "C:" {
+		t.Fatalf("expected only writable filesystems, got %+v", samples)
+	}
+
+	// 默认规则不指定目标，只读挂载点不会触发
+	rule := models.AlertRule{Metric: "disk", Operator: ">", Threshold: 95}
+	as := NewAlertingService(nil, nil)
+	for _, instance := range as.evaluateInstances(rule, samples) {
+		if instance.Triggered {
+			t.Errorf("disk %s triggered the default rule", instance.Target)
+		}
+	}
+
+	fields, err := exprFields["disk.used_percent"](data)
+	if err != nil {
+		t.Fatalf("failed to get expression field: %v", err)
+	}
+	if len(fields) != len(samples) {
+		t.Errorf("expression field returned %d disks, want %d", len(fields), len(samples))
+	}
+}
+
+func TestNetworkSamplesUseRates(t *testing.T) {
+	data := map[string]interface{}{
+		// 累计计数器不参与评估
+		"network": []models.NetworkInfo{
+			{Name: "eth0", BytesSent: 1 << 40, BytesRecv: 1 << 40, Errin: 1000, Errout: 1000},
+		},
+		"network_rates": []models.NetworkRate{
+			{Interface: "eth0", RxRate: 3 * bytesPerMB, TxRate: 1 * bytesPerMB, ErrorsRate: 2},
+			{Interface: "lo", RxRate: 0.5 * bytesPerMB, TxRate: 0.5 * bytesPerMB},
+		},
+	}
+
+	cases := []struct {
+		metric string
+		want   []float64
+	}{
+		{"network", []float64{4, 1}},
+		{"network.rx_rate", []float64{3, 0.5}},
+		{"network.tx_rate", []float64{1, 0.5}},
+		{"network_errors", []float64{2, 0}},
+		{"network.errors_rate", []float64{2, 0}},
+	}
+	for _, c := range cases {
+		samples, err := getMetricSamples(c.metric, data)
+		if err != nil {
+			t.Fatalf("%s: failed to get samples: %v", c.metric, err)
+		}
+		if len(samples) != len(c.want) {
+			t.Fatalf("%s: expected %d samples, got %+v", c.metric, len(c.want), samples)
+		}
+		for i, sample := range samples {
+			if sample.Value != c.want[i] {
+				t.Errorf("%s: %s = %v, want %v", c.metric, sample.Target, sample.Value, c.want[i])
+			}
+		}
+	}
+}
+
+func TestTargetSelectorResolution(t *testing.T) {
+	disks := []models.DiskInfo{
+		{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4", UsedPercent: 50},
+		{Device: "/dev/sda2", Mountpoint: "/var", Fstype: "ext4", UsedPercent: 95},
+		{Device: "/dev/sdb1", Mountpoint: "/var/lib/docker", Fstype: "xfs", UsedPercent: 92},
+	}
+	samples, err := getMetricSamples("disk", map[string]interface{}{"disk": disks})
+	if err != nil {
+		t.Fatalf("failed to get disk samples: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		selector string
+		want     map[string]bool // 目标 -> 是否触发
+	}{
+		{"empty evaluates every target", "", map[string]bool{"/": false, "/var": true, "/var/lib/docker": true}},
+		{"star evaluates every target", "*", map[string]bool{"/": false, "/var": true, "/var/lib/docker": true}},
+		{"mountpoint", "/var", map[string]bool{"/var": true}},
+		{"device", "/dev/sda1", map[string]bool{"/": false}},
+		{"device base name", "sdb1", map[string]bool{"/var/lib/docker": true}},
+		{"glob", "/var/*/*", map[string]bool{"/var/lib/docker": true}},
+		{"device glob", "sda*", map[string]bool{"/": false, "/var": true}},
+		{"no match", "/home", map[string]bool{}},
+		{"any", targetAny, map[string]bool{targetAny: true}},
+		{"all", targetAll, map[string]bool{targetAll: false}},
+		{"all padded", " all ", map[string]bool{targetAll: false}},
+	}
+
+	as := NewAlertingService(nil, nil)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rule := models.AlertRule{Metric: "disk", Target: c.selector, Operator: ">", Threshold: 90}
+			got := make(map[string]bool)
+			for _, instance := range as.evaluateInstances(rule, samples) {
+				got[instance.Target] = instance.Triggered
+			}
+			if len(got) != len(c.want) {
+				t.Fatalf("expected instances %v, got %v", c.want, got)
+			}
+			for target, triggered := range c.want {
+				if got[target] != triggered {
+					t.Errorf("%s: triggered = %v, want %v", target, got[target], triggered)
+				}
+			}
+		})
+	}
+}
+
+func TestAggregateSelectorValue(t *testing.T) {
+	samples := []metricSample{
+		{Target: "eth0", Names: []string{"eth0"}, Value: 10},
+		{Target: "eth1", Names: []string{"eth1"}, Value: 30},
+		{Target: "eth2", Names: []string{"eth2"}, Value: 40},
+	}
+
+	cases := []struct {
+		selector  string
+		threshold float64
+		triggered bool
+		value     float64
+	}{
+		{targetAny, 20, true, 30},  // 取首个满足条件的目标
+		{targetAny, 50, false, 10}, // 都不满足时取第一个目标
+		{targetAll, 20, false, 30},
+		{targetAll, 5, true, 10},
+	}
+	as := NewAlertingService(nil, nil)
+	for _, c := range cases {
+		rule := models.AlertRule{Metric: "network", Target: c.selector, Operator: ">", Threshold: c.threshold}
+		instances := as.evaluateInstances(rule, samples)
+		if len(instances) != 1 {
+			t.Fatalf("%s > %v: expected one aggregate instance, got %+v", c.selector, c.threshold, instances)
+		}
+		if instances[0].Triggered != c.triggered || instances[0].Value != c.value {
+			t.Errorf("%s > %v: got %+v, want triggered=%v value=%v", c.selector, c.threshold, instances[0], c.triggered, c.value)
+		}
+	}
+
+	// 没有目标时聚合告警不触发
+	rule := models.AlertRule{Metric: "network", Target: targetAll, Operator: ">", Threshold: 0}
+	if instances := as.evaluateInstances(rule, nil); len(instances) != 1 || instances[0].Triggered {
+		t.Errorf("expected an untriggered instance without targets, got %+v", instances)
+	}
+}
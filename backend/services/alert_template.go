@@ -0,0 +1,252 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/template"
+
+	"system-monitor/backend/models"
+)
+
+// 支持的界面语言，与 UIConfig.Language 一致
+const (
+	languageZhCN = "zh-CN"
+	languageEnUS = "en-US"
+)
+
+// 内置模板名称
+const (
+	templateThreshold      = "threshold"
+	templateExpression     = "expression"
+	templateAnomaly        = "anomaly"
+	templateGroup          = "group"
+	templateProcessAbsent  = "process_absent"
+	templateProcessPresent = "process_present"
+	templateEmailSubject   = "email_subject"
+	templateEmailBody      = "email_body"
+	templateTestSubject    = "test_email_subject"
+	templateTestBody       = "test_email_body"
+)
+
+// templateName 告警名称，带目标时附加目标
+const templateName = `{{.Rule.Name}}{{if .Target}} [{{.Target}}]{{end}}`
+
+// builtinTemplateText 各语言的内置模板
+var builtinTemplateText = map[string]map[string]string{
+	languageZhCN: {
+		templateThreshold:  templateName + `: 当前值 {{printf "%.2f" .Value}} {{.Operator}} 阈值 {{printf "%.2f" .Threshold}}`,
+		templateExpression: templateName + `: 条件 {{.Rule.Expression}} 成立，当前值 {{printf "%.2f" .Value}}`,
+		templateAnomaly:    templateName + `: 当前值 {{printf "%.2f" .Value}} 偏离基线 {{printf "%.2f" .Mean}}±{{printf "%.2f" .StdDev}} 达 {{printf "%.1f" .Deviation}} 倍标准差`,
+		templateGroup: `{{len .Alerts}} 条告警{{if eq .Event "resolved"}}已恢复{{else}}触发{{end}}: ` +
+			`{{range $i, $alert := .Alerts}}{{if $i}}; {{end}}{{$alert.Message}}{{end}}`,
+		templateProcessAbsent:  templateName + `: 没有正在运行的匹配进程`,
+		templateProcessPresent: templateName + `: 发现 {{printf "%.0f" .Value}} 个匹配的进程`,
+		templateEmailSubject: `[{{.Host}}] {{if gt (len .Notifications) 1}}{{len .Notifications}} 条告警通知` +
+			`{{else}}{{with index .Notifications 0}}{{if eq .Event "resolved"}}告警恢复{{else}}告警触发{{end}}: {{.Alert.RuleName}}{{end}}{{end}}`,
+		templateEmailBody: `主机: {{.Host}}
+{{range .Notifications}}
+[{{if eq .Event "resolved"}}已恢复{{else}}告警{{end}}] {{.Alert.RuleName}}{{if .Alert.Target}} ({{.Alert.Target}}){{end}}
+级别: {{.Alert.Level}}
+消息: {{.Message}}
+当前值: {{printf "%.2f" .Alert.Value}}  阈值: {{printf "%.2f" .Alert.Threshold}}
+触发时间: {{.Alert.CreatedAt.Format "2006-01-02 15:04:05"}}{{if .Alert.ResolvedAt}}
+恢复时间: {{.Alert.ResolvedAt.Format "2006-01-02 15:04:05"}}{{end}}
+{{end}}`,
+		templateTestSubject: `System Monitor 测试邮件`,
+		templateTestBody:    "这是一封来自 {{.Host}} 的测试邮件，收到说明邮件通知配置正确。\n",
+	},
+	languageEnUS: {
+		templateThreshold:  templateName + `: current value {{printf "%.2f" .Value}} is {{.Operator}} threshold {{printf "%.2f" .Threshold}}`,
+		templateExpression: templateName + `: condition {{.Rule.Expression}} is true, current value {{printf "%.2f" .Value}}`,
+		templateAnomaly:    templateName + `: current value {{printf "%.2f" .Value}} deviates from baseline {{printf "%.2f" .Mean}}±{{printf "%.2f" .StdDev}} by {{printf "%.1f" .Deviation}} standard deviations`,
+		templateGroup: `{{len .Alerts}} alerts {{if eq .Event "resolved"}}resolved{{else}}firing{{end}}: ` +
+			`{{range $i, $alert := .Alerts}}{{if $i}}; {{end}}{{$alert.Message}}{{end}}`,
+		templateProcessAbsent:  templateName + `: no matching process is running`,
+		templateProcessPresent: templateName + `: {{printf "%.0f" .Value}} matching processes found`,
+		templateEmailSubject: `[{{.Host}}] {{if gt (len .Notifications) 1}}{{len .Notifications}} alert notifications` +
+			`{{else}}{{with index .Notifications 0}}{{if eq .Event "resolved"}}Alert resolved{{else}}Alert firing{{end}}: {{.Alert.RuleName}}{{end}}{{end}}`,
+		templateEmailBody: `Host: {{.Host}}
+{{range .Notifications}}
+[{{if eq .Event "resolved"}}RESOLVED{{else}}FIRING{{end}}] {{.Alert.RuleName}}{{if .Alert.Target}} ({{.Alert.Target}}){{end}}
+Level: {{.Alert.Level}}
+Message: {{.Message}}
+Value: {{printf "%.2f" .Alert.Value}}  Threshold: {{printf "%.2f" .Alert.Threshold}}
+Fired at: {{.Alert.CreatedAt.Format "2006-01-02 15:04:05"}}{{if .Alert.ResolvedAt}}
+Resolved at: {{.Alert.ResolvedAt.Format "2006-01-02 15:04:05"}}{{end}}
+{{end}}`,
+		templateTestSubject: `System Monitor test email`,
+		templateTestBody:    "This is a test email from {{.Host}}. If you received it, email notifications are configured correctly.\n",
+	},
+}
+
+// operatorText 各语言的比较操作符文本
+var operatorText = map[string]map[string]string{
+	languageZhCN: {
+		">":  "大于",
+		">=": "大于等于",
+		"<":  "小于",
+		"<=": "小于等于",
+		"==": "等于",
+		"!=": "不等于",
+	},
+	languageEnUS: {
+		">":  "greater than",
+		">=": "greater than or equal to",
+		"<":  "less than",
+		"<=": "less than or equal to",
+		"==": "equal to",
+		"!=": "not equal to",
+	},
+}
+
+// builtinTemplates 解析后的内置模板，按 语言/名称 索引
+var builtinTemplates = parseBuiltinTemplates()
+
+// messageData 告警消息模板可使用的数据
+type messageData struct {
+	Rule      models.AlertRule
+	Alert     models.Alert      // 仅通知模板可用
+	Event     string            // firing 或 resolved，仅通知模板可用
+	Host      string            // 主机名
+	Target    string            // 告警目标，如挂载点或网络接口
+	Labels    map[string]string // 标签：host、target、metric、rule、level
+	Value     float64
+	Threshold float64
+	Operator  string // 本地化的比较操作符文本
+	Mean      float64
+	StdDev    float64
+	Deviation float64 // 偏离基线的标准差倍数，仅异常检测规则可用
+}
+
+// newMessageData 构建告警消息模板数据
+func newMessageData(rule models.AlertRule, host, target string, value float64, language string) messageData {
+	return messageData{
+		Rule:      rule,
+		Host:      host,
+		Target:    target,
+		Labels:    messageLabels(rule, host, target, ""),
+		Value:     value,
+		Threshold: rule.Threshold,
+		Operator:  localizeOperator(language, rule.Operator),
+	}
+}
+
+// newNotificationData 构建通知模板数据
+func newNotificationData(notification models.Notification) messageData {
+	alert := notification.Alert
+	return messageData{
+		Rule:      notification.Rule,
+		Alert:     alert,
+		Event:     notification.Event,
+		Host:      notification.Host,
+		Target:    alert.Target,
+		Labels:    messageLabels(notification.Rule, notification.Host, alert.Target, alert.Level),
+		Value:     alert.Value,
+		Threshold: alert.Threshold,
+		Operator:  localizeOperator(notification.Language, notification.Rule.Operator),
+	}
+}
+
+// messageLabels 构建模板中的标签
+func messageLabels(rule models.AlertRule, host, target, level string) map[string]string {
+	return map[string]string{
+		"host":   host,
+		"target": target,
+		"metric": rule.Metric,
+		"rule":   rule.Name,
+		"level":  level,
+	}
+}
+
+// normalizeLanguage 规范化语言代码，不支持的语言使用中文
+func normalizeLanguage(language string) string {
+	if strings.HasPrefix(strings.ToLower(language), "en") {
+		return languageEnUS
+	}
+	return languageZhCN
+}
+
+// localizeOperator 获取比较操作符的本地化文本
+func localizeOperator(language, operator string) string {
+	if text, ok := operatorText[normalizeLanguage(language)][operator]; ok {
+		return text
+	}
+	return operator
+}
+
+// parseBuiltinTemplates 解析所有内置模板
+func parseBuiltinTemplates() map[string]*template.Template {
+	templates := make(map[string]*template.Template)
+	for language, texts := range builtinTemplateText {
+		for name, text := range texts {
+			key := language + "/" + name
+			templates[key] = template.Must(template.New(key).Parse(text))
+		}
+	}
+	return templates
+}
+
+// builtinTemplate 获取指定语言的内置模板
+func builtinTemplate(language, name string) *template.Template {
+	return builtinTemplates[normalizeLanguage(language)+"/"+name]
+}
+
+// renderBuiltin 使用内置模板渲染
+func renderBuiltin(language, name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := builtinTemplate(language, name).Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// renderMessage 渲染告警消息，自定义模板为空或渲染失败时使用内置模板
+func renderMessage(custom, language, name string, data messageData) string {
+	if custom != "" {
+		message, err := renderCustomTemplate(custom, data)
+		if err == nil {
+			return message
+		}
+		log.Printf("Failed to render message template of rule %s: %v", data.Rule.Name, err)
+	}
+
+	message, err := renderBuiltin(language, name, data)
+	if err != nil {
+		log.Printf("%v", err)
+		return data.Rule.Name
+	}
+	return message
+}
+
+// renderCustomTemplate 渲染用户定义的消息模板
+func renderCustomTemplate(text string, data messageData) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid message template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render message template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// validateMessageTemplate 检查消息模板能否解析，并用示例数据试渲染以发现不存在的字段
+func validateMessageTemplate(text string) error {
+	if text == "" {
+		return nil
+	}
+
+	tmpl, err := template.New("message").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid message template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, messageData{Labels: map[string]string{}}); err != nil {
+		return fmt.Errorf("invalid message template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"sync"
+	"testing"
+
+	"system-monitor/backend/models"
+)
+
+func TestFormatAlertMessage(t *testing.T) {
+	rule := models.AlertRule{Name: "Disk usage", Metric: "disk", Operator: ">", Threshold: 90}
+
+	cases := []struct {
+		name     string
+		language string
+		template string
+		want     string
+	}{
+		{"chinese", "zh-CN", "", "Disk usage [/var]: 当前值 95.50 大于 阈值 90.00"},
+		{"english", "en-US", "", "Disk usage [/var]: current value 95.50 is greater than threshold 90.00"},
+		{"english variant", "en-GB", "", "Disk usage [/var]: current value 95.50 is greater than threshold 90.00"},
+		{"unsupported language", "fr-FR", "", "Disk usage [/var]: 当前值 95.50 大于 阈值 90.00"},
+		{"custom template", "en-US", `{{.Labels.host}} {{.Target}} {{printf "%.0f" .Value}}/{{.Threshold}} {{.Operator}}`,
+			"web-1 /var 96/90 greater than"},
+		{"custom template render error", "en-US", `{{index .Rule.Actions 3}}`,
+			"Disk usage [/var]: current value 95.50 is greater than threshold 90.00"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			as := NewAlertingService(nil, nil)
+			as.host = "web-1"
+			as.SetLanguage(c.language)
+
+			rule := rule
+			rule.MessageTemplate = c.template
+			if got := as.formatAlertMessage(rule, "/var", 95.5); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestValidateMessageTemplate(t *testing.T) {
+	cases := []struct {
+		template string
+		ok       bool
+	}{
+		{"", true},
+		{"{{.Rule.Name}} on {{.Labels.host}}: {{.Value}}", true},
+		{"{{.Labels.missing}}", true}, // 不存在的标签为空
+		{"{{.Value", false},
+		{"{{.Unknown}}", false},
+		{"{{.Rule.Unknown}}", false},
+	}
+	for _, c := range cases {
+		if err := validateMessageTemplate(c.template); (err == nil) != c.ok {
+			t.Errorf("%q: expected ok=%v, got %v", c.template, c.ok, err)
+		}
+	}
+}
+
+// messageChannel 记录收到的通知消息
+type messageChannel struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (c *messageChannel) Send(target string, notification models.Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, notification.Message)
+	return nil
+}
+
+func TestActionTemplateMessage(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		event    string
+		want     string
+	}{
+		{"alert message", "", notificationFiring, "Disk / is almost full"},
+		{"firing", `{{.Event}} {{.Labels.level}} {{.Alert.RuleName}}@{{.Host}}`, notificationFiring, "firing warning Disk usage@test-host"},
+		{"resolved", `{{if eq .Event "resolved"}}OK{{else}}ALERT{{end}} {{.Target}}`, notificationResolved, "OK /"},
+		{"render error", `{{index .Rule.Actions 3}}`, notificationFiring, "Disk / is almost full"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			channel := &messageChannel{}
+			ns := NewNotificationService(nil)
+			ns.host = "test-host"
+			ns.RegisterChannel("webhook", channel)
+
+			notification := testNotification(c.event)
+			ns.Dispatch(models.AlertAction{Type: "webhook", Template: c.template}, notification.Rule, notification.Alert, c.event)
+			ns.Wait()
+
+			if len(channel.messages) != 1 || channel.messages[0] != c.want {
+				t.Errorf("got %q, want %q", channel.messages, c.want)
+			}
+		})
+	}
+}
@@ -3,47 +3,227 @@ package services
 import (
 	"fmt"
 	"log"
+	"os"
+	"sort"
+	"sync"
 	"time"
 
 	"system-monitor/backend/models"
 )
 
-// AlertingService 告警服务
+// defaultRulesSeededKey 记录默认告警规则是否已经创建
+const defaultRulesSeededKey = "alert_rules_seeded"
+
+// AlertingService 告警服务，可被监控协程和前端调用并发使用
 type AlertingService struct {
-	config    interface{} // 实际应用中应该是具体的配置类型
-	eventMgr  *EventManager
-	rules     []models.AlertRule
-	active    map[int64]*models.Alert
-	alertChan chan *models.Alert
+	mu         sync.RWMutex // 保护规则、告警和静默状态
+	checkMu    sync.Mutex   // 串行执行告警检查，评估规则期间不持有 mu
+	cacheMu    sync.Mutex   // 保护基线、磁盘预测和表达式缓存，评估规则时在 mu 之外使用
+	config     interface{}  // 实际应用中应该是具体的配置类型
+	eventMgr   *EventManager
+	storage    *StorageService
+	notifier   *NotificationService
+	remediator *RemediationService
+	rules      []models.AlertRule
+	active     map[string]*models.Alert          // 按 alertKey 索引的活动告警
+	pending    map[string]*models.Alert          // 条件已满足但未达到持续时间的告警
+	silences   []models.AlertSilence             // 未过期的告警静默
+	windows    []maintenanceSchedule             // 维护窗口
+	baselines  map[string]anomalyBaseline        // 异常检测基线缓存，由 cacheMu 保护
+	flaps      map[string]*flapState             // 按 alertKey 索引的抖动状态
+	fired      []*models.Alert                   // 本次检查新触发、等待计算抑制状态后通知的告警
+	delivered  map[*models.Alert]*firingDelivery // 已发送触发通知的活动告警，解决时通知同样的渠道
+	groups     map[string]*alertGroup            // 等待发送的通知分组
+	groupBy    []string                          // 通知分组依据的标签
+	groupWait  time.Duration                     // 通知分组等待时间，为0时不分组
+	forecasts  []models.DiskForecast             // 磁盘写满预测缓存，由 cacheMu 保护
+	forecastAt time.Time
+	exprCache  map[string]exprNode // 已解析的规则表达式，由 cacheMu 保护
+	alertChan  chan *models.Alert
+	nextID     int64 // 无存储服务时使用的内存ID
+	host       string
+	language   string // 告警消息使用的语言
+	now        func() time.Time
 }
 
 // NewAlertingService 创建新的告警服务
 func NewAlertingService(config interface{}, eventMgr *EventManager) *AlertingService {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
 	return &AlertingService{
 		config:    config,
 		eventMgr:  eventMgr,
 		rules:     make([]models.AlertRule, 0),
-		active:    make(map[int64]*models.Alert),
+		active:    make(map[string]*models.Alert),
+		pending:   make(map[string]*models.Alert),
+		silences:  make([]models.AlertSilence, 0),
+		baselines: make(map[string]anomalyBaseline),
+		exprCache: make(map[string]exprNode),
+		flaps:     make(map[string]*flapState),
+		groups:    make(map[string]*alertGroup),
+		delivered: make(map[*models.Alert]*firingDelivery),
 		alertChan: make(chan *models.Alert, 100),
+		host:      host,
+		language:  languageZhCN,
+		now:       time.Now,
 	}
 }
 
+// SetStorageService 设置存储服务
+func (as *AlertingService) SetStorageService(storage *StorageService) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.storage = storage
+}
+
+// SetNotificationService 设置通知服务，用于向外部渠道发送告警
+func (as *AlertingService) SetNotificationService(notifier *NotificationService) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.notifier = notifier
+}
+
+// SetRemediationService 设置修复动作服务，未设置时不执行修复动作
+func (as *AlertingService) SetRemediationService(remediator *RemediationService) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.remediator = remediator
+}
+
+// SetLanguage 设置告警消息使用的语言，如 zh-CN、en-US
+func (as *AlertingService) SetLanguage(language string) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.language = normalizeLanguage(language)
+}
+
+// LoadRules 从数据库加载告警规则
+func (as *AlertingService) LoadRules() error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if as.storage == nil {
+		return fmt.Errorf("storage service not available")
+	}
+
+	rules, err := as.storage.GetAlertRules()
+	if err != nil {
+		return fmt.Errorf("failed to load alert rules: %w", err)
+	}
+
+	as.rules = rules
+	log.Printf("Loaded %d alert rules", len(rules))
+
+	// 恢复上次运行时仍处于活动状态的告警
+	alerts, err := as.storage.QueryAlerts(models.AlertQuery{Status: "active"})
+	if err != nil {
+		return fmt.Errorf("failed to load active alerts: %w", err)
+	}
+
+	for i := range alerts {
+		alert := alerts[i]
+		key := alertKey(alert.RuleID, alert.Target)
+		if _, exists := as.active[key]; exists || !as.hasRule(alert.RuleID) {
+			// 规则已不存在或存在重复告警，直接标记为已解决
+			as.resolveAlert(&alert)
+			continue
+		}
+		as.active[key] = &alert
+	}
+
+	// 恢复未过期的告警静默
+	silences, err := as.storage.GetSilences(true)
+	if err != nil {
+		return fmt.Errorf("failed to load alert silences: %w", err)
+	}
+	as.silences = silences
+
+	if err := as.loadMaintenanceWindows(); err != nil {
+		return fmt.Errorf("failed to load maintenance windows: %w", err)
+	}
+
+	now := as.now()
+	for _, alert := range as.active {
+		alert.Silenced = as.isSilenced(alert, now)
+	}
+
+	return nil
+}
+
+// NeedsProcessTable 检查是否有启用的规则需要完整进程表，监控服务据此决定是否收集
+func (as *AlertingService) NeedsProcessTable() bool {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+	for _, rule := range as.rules {
+		if rule.Enabled && (rule.Type == ruleTypeProcess || rule.Metric == "processes") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRule 检查规则是否存在，调用方需持有锁
+func (as *AlertingService) hasRule(id int64) bool {
+	return as.ruleIndex(id) >= 0
+}
+
+// ruleIndex 返回规则在列表中的位置，不存在时返回 -1，调用方需持有锁
+func (as *AlertingService) ruleIndex(id int64) int {
+	for i, rule := range as.rules {
+		if rule.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// findRule 按ID查找规则，调用方需持有锁
+func (as *AlertingService) findRule(ruleID int64) (models.AlertRule, bool) {
+	for _, rule := range as.rules {
+		if rule.ID == ruleID {
+			return rule, true
+		}
+	}
+	return models.AlertRule{}, false
+}
+
 // GetRules 获取告警规则
 func (as *AlertingService) GetRules() ([]models.AlertRule, error) {
-	return as.rules, nil
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	// 返回副本，避免调用方读取时规则被并发修改
+	rules := make([]models.AlertRule, len(as.rules))
+	copy(rules, as.rules)
+	return rules, nil
 }
 
 // CreateRule 创建告警规则
 func (as *AlertingService) CreateRule(rule models.AlertRule) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
 	// 验证规则
 	if err := as.validateRule(rule); err != nil {
 		return fmt.Errorf("invalid rule: %w", err)
 	}
 
-	// 设置ID和创建时间
-	rule.ID = time.Now().UnixNano()
+	// 设置创建时间
 	rule.CreatedAt = time.Now()
-	rule.UpdatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+
+	// 持久化并使用数据库分配的ID
+	if as.storage != nil {
+		if err := as.storage.CreateAlertRule(&rule); err != nil {
+			return fmt.Errorf("failed to save rule: %w", err)
+		}
+	} else {
+		as.nextID++
+		rule.ID = as.nextID
+	}
 
 	as.rules = append(as.rules, rule)
 	log.Printf("Created alert rule: %s", rule.Name)
@@ -53,154 +233,341 @@ func (as *AlertingService) CreateRule(rule models.AlertRule) error {
 
 // UpdateRule 更新告警规则
 func (as *AlertingService) UpdateRule(rule models.AlertRule) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
 	// 查找规则
-	for i, r := range as.rules {
-		if r.ID == rule.ID {
-			// 验证规则
-			if err := as.validateRule(rule); err != nil {
-				return fmt.Errorf("invalid rule: %w", err)
-			}
+	i := as.ruleIndex(rule.ID)
+	if i < 0 {
+		return fmt.Errorf("rule with ID %d not found", rule.ID)
+	}
 
-			rule.UpdatedAt = time.Now()
-			as.rules[i] = rule
-			log.Printf("Updated alert rule: %s", rule.Name)
-			return nil
+	// 验证规则
+	if err := as.validateRule(rule); err != nil {
+		return fmt.Errorf("invalid rule: %w", err)
+	}
+
+	rule.CreatedAt = as.rules[i].CreatedAt
+	rule.UpdatedAt = time.Now()
+
+	if as.storage != nil {
+		if err := as.storage.UpdateAlertRule(rule); err != nil {
+			return fmt.Errorf("failed to save rule: %w", err)
 		}
 	}
 
-	return fmt.Errorf("rule with ID %d not found", rule.ID)
+	as.replaceRule(i, rule)
+	log.Printf("Updated alert rule: %s", rule.Name)
+	return nil
+}
+
+// replaceRule 替换已保存的规则，并重置与旧定义相关的状态，调用方需持有锁
+func (as *AlertingService) replaceRule(i int, rule models.AlertRule) {
+	// 规则变化后重新计算等待时间和基线
+	as.clearPending(rule.ID)
+	as.cacheMu.Lock()
+	as.baselines = make(map[string]anomalyBaseline)
+	as.exprCache = make(map[string]exprNode)
+	as.cacheMu.Unlock()
+
+	as.rules[i] = rule
+
+	// 关闭抖动检测后不再抑制通知
+	if rule.Flap == nil {
+		as.clearFlapping(rule.ID)
+		for _, alert := range as.active {
+			if alert.RuleID == rule.ID {
+				alert.Flapping = false
+			}
+		}
+	}
 }
 
 // DeleteRule 删除告警规则
 func (as *AlertingService) DeleteRule(id int64) error {
-	for i, rule := range as.rules {
-		if rule.ID == id {
-			as.rules = append(as.rules[:i], as.rules[i+1:]...)
-
-			// 取消相关的活动告警
-			if alert, exists := as.active[id]; exists {
-				alert.Status = "resolved"
-				alert.ResolvedAt = &[]time.Time{time.Now()}[0]
-				as.eventMgr.EmitAlertResolved(alert)
-				delete(as.active, id)
-			}
+	as.mu.Lock()
+	defer as.mu.Unlock()
 
-			log.Printf("Deleted alert rule with ID: %d", id)
-			return nil
+	i := as.ruleIndex(id)
+	if i < 0 {
+		return fmt.Errorf("rule with ID %d not found", id)
+	}
+
+	if as.storage != nil {
+		if err := as.storage.DeleteAlertRule(id); err != nil {
+			return fmt.Errorf("failed to delete rule: %w", err)
+		}
+	}
+
+	as.removeRule(i)
+	log.Printf("Deleted alert rule with ID: %d", id)
+	return nil
+}
+
+// removeRule 移除已删除的规则，并解决其活动告警，调用方需持有锁
+func (as *AlertingService) removeRule(i int) {
+	id := as.rules[i].ID
+
+	// 先解决相关的活动告警，解决通知仍需按规则的动作发送
+	for key, alert := range as.active {
+		if alert.RuleID == id {
+			as.resolveActive(key, alert)
 		}
 	}
 
-	return fmt.Errorf("rule with ID %d not found", id)
+	as.rules = append(as.rules[:i], as.rules[i+1:]...)
+	as.clearPending(id)
+	as.clearFlapping(id)
 }
 
-// CheckAlerts 检查告警
+// CheckAlerts 检查告警。规则在锁外评估，查询历史数据时不阻塞规则修改和界面查询，
+// 评估完成后重新加锁更新告警状态
 func (as *AlertingService) CheckAlerts(data map[string]interface{}) error {
+	as.checkMu.Lock()
+	defer as.checkMu.Unlock()
+
+	as.mu.Lock()
+	now := as.now()
+	as.expireSilences(now)
+	as.updateMaintenance(now)
+	as.escalate(now)
+
+	rules := make([]models.AlertRule, 0, len(as.rules))
 	for _, rule := range as.rules {
-		if !rule.Enabled {
-			continue
+		if rule.Enabled {
+			rules = append(rules, rule)
 		}
+	}
+	eval := &ruleEvaluator{as: as, storage: as.storage, host: as.host, language: as.language, now: now}
+	as.mu.Unlock()
 
-		if err := as.evaluateRule(rule, data); err != nil {
+	results := make([]ruleResult, 0, len(rules))
+	for _, rule := range rules {
+		instances, err := eval.evaluate(rule, data)
+		if err != nil {
 			log.Printf("Error evaluating rule %s: %v", rule.Name, err)
+			continue
 		}
+		results = append(results, ruleResult{rule: rule, instances: instances})
 	}
 
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	for _, result := range results {
+		// 评估期间被修改、禁用或删除的规则丢弃本次结果，下次检查时按新规则评估
+		current, ok := as.findRule(result.rule.ID)
+		if !ok || !current.Enabled || !current.UpdatedAt.Equal(result.rule.UpdatedAt) {
+			continue
+		}
+		as.applyInstances(current, result.instances, now)
+	}
+
+	// 所有规则的告警状态更新后再计算抑制关系和发送通知，结果与规则的顺序无关
+	as.updateInhibitions()
+	as.notifyFired()
+	as.flushGroups(now)
+
 	return nil
 }
 
-// evaluateRule 评估告警规则
-func (as *AlertingService) evaluateRule(rule models.AlertRule, data map[string]interface{}) error {
-	// 获取指标值
-	value, err := as.getMetricValue(rule.Metric, data)
-	if err != nil {
-		return fmt.Errorf("failed to get metric value: %w", err)
+// ruleEvaluator 在不持有告警服务锁的情况下计算规则的告警实例，只使用检查开始时在锁内取得的快照
+type ruleEvaluator struct {
+	as       *AlertingService // 只访问由 cacheMu 保护的缓存
+	storage  *StorageService
+	host     string
+	language string
+	now      time.Time
+}
+
+// ruleResult 单个规则的评估结果
+type ruleResult struct {
+	rule      models.AlertRule
+	instances []alertInstance
+}
+
+// evaluate 计算规则各目标的告警实例
+func (e *ruleEvaluator) evaluate(rule models.AlertRule, data map[string]interface{}) ([]alertInstance, error) {
+	if rule.Type == ruleTypeAnomaly {
+		instances, err := e.evaluateAnomaly(rule, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect anomaly: %w", err)
+		}
+		return instances, nil
 	}
 
-	// 评估条件
-	isTriggered := as.evaluateCondition(value, rule.Operator, rule.Threshold)
+	if rule.Type == ruleTypeProcess {
+		instances, err := e.evaluateProcess(rule, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate processes: %w", err)
+		}
+		return instances, nil
+	}
 
-	// 检查是否已有活动告警
-	activeAlert, exists := as.active[rule.ID]
+	if rule.Expression != "" {
+		// 表达式规则只产生一个告警实例
+		triggered, value, err := e.evaluateExpression(rule, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate expression: %w", err)
+		}
+		return []alertInstance{{Value: value, Triggered: triggered}}, nil
+	}
 
-	if isTriggered && !exists {
-		// 创建新告警
-		alert := &models.Alert{
-			ID:        time.Now().UnixNano(),
-			RuleID:    rule.ID,
-			RuleName:  rule.Name,
-			Message:   as.formatAlertMessage(rule, value),
-			Level:     rule.Actions[0].Level, // 使用第一个动作的级别
-			Value:     value,
-			Threshold: rule.Threshold,
-			Status:    "active",
-			CreatedAt: time.Now(),
+	// 获取指标值，磁盘写满预测来自历史数据
+	var samples []metricSample
+	var err error
+	if rule.Metric == diskForecastMetric {
+		samples, err = e.diskForecastSamples()
+	} else {
+		samples, err = getMetricSamples(rule.Metric, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metric value: %w", err)
+	}
+	return e.as.evaluateInstances(rule, samples), nil
+}
+
+// applyInstances 按评估结果更新规则各目标的告警状态，每个目标独立触发和解决，调用方需持有锁
+func (as *AlertingService) applyInstances(rule models.AlertRule, instances []alertInstance, now time.Time) {
+	seen := make(map[string]bool)
+	for _, instance := range instances {
+		key := alertKey(rule.ID, instance.Target)
+		seen[key] = true
+		as.evaluateInstance(rule, key, instance, now)
+	}
+
+	// 目标已消失或不再匹配选择器时，解决对应的告警
+	for key, alert := range as.pending {
+		if alert.RuleID == rule.ID && !seen[key] {
+			delete(as.pending, key)
+		}
+	}
+	for key, alert := range as.active {
+		if alert.RuleID == rule.ID && !seen[key] {
+			as.resolveActive(key, alert)
 		}
+	}
 
-		as.active[rule.ID] = alert
+	as.updateFlapping(rule, now)
+}
 
-		// 发送告警事件
-		as.eventMgr.EmitAlert(alert)
+// evaluateInstance 更新单个告警实例的状态，调用方需持有锁
+func (as *AlertingService) evaluateInstance(rule models.AlertRule, key string, instance alertInstance, now time.Time) {
+	activeAlert, exists := as.active[key]
 
-		log.Printf("Alert triggered: %s (Value: %.2f, Threshold: %.2f)",
-			rule.Name, value, rule.Threshold)
+	if !instance.Triggered {
+		// 条件不再满足时重置等待状态
+		delete(as.pending, key)
 
-	} else if !isTriggered && exists {
-		// 解决告警
-		activeAlert.Status = "resolved"
-		now := time.Now()
-		activeAlert.ResolvedAt = &now
+		// 设置了恢复阈值时，活动告警越过恢复阈值才解决
+		if exists && !as.holdsActive(rule, instance.Value) {
+			as.resolveActive(key, activeAlert)
+		}
+		return
+	}
 
-		// 发送告警解决事件
-		as.eventMgr.EmitAlertResolved(activeAlert)
+	if exists {
+		return
+	}
 
-		delete(as.active, rule.ID)
-		log.Printf("Alert resolved: %s", rule.Name)
+	// 条件首次满足时进入等待状态
+	message := instance.Message
+	if message == "" {
+		message = as.formatAlertMessage(rule, instance.Target, instance.Value)
 	}
 
-	return nil
+	threshold := rule.Threshold
+	if rule.Type == ruleTypeAnomaly {
+		threshold = anomalyConfig(rule).Sigma
+	}
+	pendingAlert, isPending := as.pending[key]
+	if !isPending {
+		since := now
+		pendingAlert = &models.Alert{
+			RuleID:       rule.ID,
+			RuleName:     rule.Name,
+			Target:       instance.Target,
+			Message:      message,
+			Level:        rule.Actions[0].Level, // 使用第一个动作的级别
+			Value:        instance.Value,
+			Threshold:    threshold,
+			Status:       "pending",
+			CreatedAt:    now,
+			PendingSince: &since,
+		}
+		as.pending[key] = pendingAlert
+	} else {
+		pendingAlert.Value = instance.Value
+		pendingAlert.Message = message
+	}
+
+	// 持续满足条件达到规则的持续时间后才触发
+	if now.Sub(*pendingAlert.PendingSince) < rule.Duration {
+		return
+	}
+
+	delete(as.pending, key)
+	as.fireAlert(key, pendingAlert, now)
 }
 
-// getMetricValue 获取指标值
-func (as *AlertingService) getMetricValue(metric string, data map[string]interface{}) (float64, error) {
-	switch metric {
-	case "cpu":
-		if cpuData, ok := data["cpu"]; ok {
-			if cpuInfo, ok := cpuData.(*models.CPUInfo); ok {
-				return cpuInfo.Usage, nil
-			}
-		}
-	case "memory":
-		if memData, ok := data["memory"]; ok {
-			if memInfo, ok := memData.(*models.MemoryInfo); ok {
-				return memInfo.UsedPercent, nil
-			}
-		}
-	case "disk":
-		if diskData, ok := data["disk"]; ok {
-			if disks, ok := diskData.([]models.DiskInfo); ok && len(disks) > 0 {
-				// 返回第一个磁盘的使用率
-				return disks[0].UsedPercent, nil
-			}
-		}
-	case "network":
-		if netData, ok := data["network"]; ok {
-			if nets, ok := netData.([]models.NetworkInfo); ok && len(nets) > 0 {
-				// 返回第一个网络接口的流量总和
-				total := nets[0].BytesSent + nets[0].BytesRecv
-				return float64(total), nil
-			}
+// resolveActive 解决活动告警并发送通知，调用方需持有锁
+func (as *AlertingService) resolveActive(key string, alert *models.Alert) {
+	as.resolveAlert(alert)
+	as.recordStateChange(alert, *alert.ResolvedAt)
+
+	// 发送告警解决事件
+	as.notifyResolved(alert)
+
+	delete(as.active, key)
+	log.Printf("Alert resolved: %s", alert.RuleName)
+}
+
+// clearPending 清除规则所有等待中的告警，调用方需持有锁
+func (as *AlertingService) clearPending(ruleID int64) {
+	for key, alert := range as.pending {
+		if alert.RuleID == ruleID {
+			delete(as.pending, key)
 		}
-	case "processes":
-		if procData, ok := data["processes"]; ok {
-			if procs, ok := procData.([]models.ProcessInfo); ok {
-				return float64(len(procs)), nil
-			}
+	}
+}
+
+// fireAlert 将等待中的告警转为触发状态，调用方需持有锁
+func (as *AlertingService) fireAlert(key string, alert *models.Alert, now time.Time) {
+	alert.ID = now.UnixNano()
+	alert.Status = "active"
+	alert.CreatedAt = now
+	alert.Silenced = as.isSilenced(alert, now)
+	alert.Suppressed = as.inMaintenance(alert.RuleID, now)
+
+	// 记录告警历史
+	if as.storage != nil {
+		if err := as.storage.InsertAlert(alert); err != nil {
+			log.Printf("Failed to record alert %s: %v", alert.RuleName, err)
 		}
-	default:
-		return 0, fmt.Errorf("unknown metric: %s", metric)
 	}
 
-	return 0, fmt.Errorf("metric data not found: %s", metric)
+	as.active[key] = alert
+	as.recordStateChange(alert, now)
+	as.remediateFired(alert)
+
+	// 抑制状态在本次检查的所有规则评估后确定，之后再发送告警事件
+	as.fired = append(as.fired, alert)
+
+	log.Printf("Alert triggered: %s (Value: %.2f, Threshold: %.2f)",
+		alert.RuleName, alert.Value, alert.Threshold)
+}
+
+// resolveAlert 将告警标记为已解决并更新历史记录
+func (as *AlertingService) resolveAlert(alert *models.Alert) {
+	now := as.now()
+	alert.Status = "resolved"
+	alert.ResolvedAt = &now
+
+	if as.storage != nil {
+		if err := as.storage.ResolveAlert(alert); err != nil {
+			log.Printf("Failed to update alert %d: %v", alert.ID, err)
+		}
+	}
 }
 
 // evaluateCondition 评估条件
@@ -223,44 +590,51 @@ func (as *AlertingService) evaluateCondition(value float64, operator string, thr
 	}
 }
 
-// formatAlertMessage 格式化告警消息
-func (as *AlertingService) formatAlertMessage(rule models.AlertRule, value float64) string {
-	return fmt.Sprintf("%s: 当前值 %.2f %s 阈值 %.2f",
-		rule.Name, value, as.getOperatorText(rule.Operator), rule.Threshold)
-}
-
-// getOperatorText 获取操作符文本
-func (as *AlertingService) getOperatorText(operator string) string {
-	switch operator {
-	case ">":
-		return "大于"
-	case ">=":
-		return "大于等于"
-	case "<":
-		return "小于"
-	case "<=":
-		return "小于等于"
-	case "==":
-		return "等于"
-	case "!=":
-		return "不等于"
-	default:
-		return operator
+// formatAlertMessage 按规则的消息模板或界面语言的内置模板格式化告警消息，调用方需持有锁
+func (as *AlertingService) formatAlertMessage(rule models.AlertRule, target string, value float64) string {
+	name := templateThreshold
+	if rule.Expression != "" {
+		name = templateExpression
 	}
+	data := newMessageData(rule, as.host, target, value, as.language)
+	return renderMessage(rule.MessageTemplate, as.language, name, data)
 }
 
-// validateRule 验证规则
+// validateRule 验证规则，调用方需持有锁
 func (as *AlertingService) validateRule(rule models.AlertRule) error {
 	if rule.Name == "" {
 		return fmt.Errorf("rule name cannot be empty")
 	}
 
-	if rule.Metric == "" {
-		return fmt.Errorf("metric cannot be empty")
+	switch rule.Type {
+	case "", ruleTypeThreshold, ruleTypeAnomaly, ruleTypeProcess:
+	default:
+		return fmt.Errorf("unknown rule type: %s", rule.Type)
 	}
 
-	if rule.Operator == "" {
-		return fmt.Errorf("operator cannot be empty")
+	if rule.Type == ruleTypeAnomaly {
+		// 异常检测规则的 Operator 表示偏离方向，为空时双向检测
+		if err := validateAnomalyRule(rule); err != nil {
+			return err
+		}
+	} else if rule.Type == ruleTypeProcess {
+		// 进程规则不使用 Metric，按进程匹配条件评估
+		if err := validateProcessRule(rule); err != nil {
+			return err
+		}
+	} else if rule.Expression != "" {
+		// 表达式规则不使用 Metric/Operator
+		if _, err := parseExpression(rule.Expression); err != nil {
+			return fmt.Errorf("invalid expression: %w", err)
+		}
+	} else {
+		if rule.Metric == "" {
+			return fmt.Errorf("metric cannot be empty")
+		}
+
+		if rule.Operator == "" {
+			return fmt.Errorf("operator cannot be empty")
+		}
 	}
 
 	if rule.Threshold < 0 {
@@ -271,62 +645,482 @@ func (as *AlertingService) validateRule(rule models.AlertRule) error {
 		return fmt.Errorf("duration cannot be negative")
 	}
 
+	if err := validateTarget(rule.Target); err != nil {
+		return err
+	}
+
 	if len(rule.Actions) == 0 {
 		return fmt.Errorf("at least one action is required")
 	}
 
+	if err := validateHysteresis(rule); err != nil {
+		return err
+	}
+	if err := validateFlap(rule); err != nil {
+		return err
+	}
+	if err := validateEscalation(rule); err != nil {
+		return err
+	}
+	if err := validateInhibits(rule); err != nil {
+		return err
+	}
+	if err := as.validateRemediation(rule); err != nil {
+		return err
+	}
+
+	if err := validateMessageTemplate(rule.MessageTemplate); err != nil {
+		return err
+	}
+
+	for _, action := range rule.Actions {
+		if !alertActionTypes[action.Type] {
+			return fmt.Errorf("unsupported action type: %s", action.Type)
+		}
+		if err := validateMessageTemplate(action.Template); err != nil {
+			return fmt.Errorf("action %s: %w", action.Type, err)
+		}
+	}
+
 	return nil
 }
 
-// GetAlerts 获取告警列表
+// GetAlerts 获取告警列表（活动告警和历史告警）
 func (as *AlertingService) GetAlerts(limit int) ([]models.Alert, error) {
-	var alerts []models.Alert
+	return as.QueryAlerts(models.AlertQuery{Limit: limit})
+}
 
-	// 添加活动告警
-	for _, alert := range as.active {
-		alerts = append(alerts, *alert)
+// QueryAlerts 按级别、规则和时间范围查询告警，按触发时间倒序排列
+func (as *AlertingService) QueryAlerts(query models.AlertQuery) ([]models.Alert, error) {
+	as.mu.RLock()
+	storage := as.storage
+	as.mu.RUnlock()
+
+	// 无存储服务时只能返回活动告警
+	if storage == nil {
+		as.mu.RLock()
+		defer as.mu.RUnlock()
+
+		alerts := make([]models.Alert, 0)
+		for _, alert := range as.active {
+			if alertMatches(*alert, query) {
+				alerts = append(alerts, *alert)
+			}
+		}
+
+		sort.Slice(alerts, func(i, j int) bool {
+			return alerts[i].CreatedAt.After(alerts[j].CreatedAt)
+		})
+
+		if query.Limit > 0 && len(alerts) > query.Limit {
+			alerts = alerts[:query.Limit]
+		}
+		return alerts, nil
+	}
+
+	alerts, err := storage.QueryAlerts(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
 	}
 
-	// 这里应该从数据库获取历史告警，暂时返回空列表
-	// TODO: 实现历史告警查询
+	as.mu.RLock()
+	defer as.mu.RUnlock()
 
-	// 限制数量
-	if limit > 0 && len(alerts) > limit {
-		alerts = alerts[:limit]
+	// 活动告警以内存中的状态为准
+	for i := range alerts {
+		if active, exists := as.active[alertKey(alerts[i].RuleID, alerts[i].Target)]; exists && active.ID == alerts[i].ID {
+			alerts[i] = *active
+		}
 	}
 
 	return alerts, nil
 }
 
-// GetActiveAlerts 获取活动告警
+// alertMatches 检查告警是否满足查询条件
+func alertMatches(alert models.Alert, query models.AlertQuery) bool {
+	if query.Level != "" && alert.Level != query.Level {
+		return false
+	}
+	if query.RuleID != 0 && alert.RuleID != query.RuleID {
+		return false
+	}
+	if query.Status != "" && alert.Status != query.Status {
+		return false
+	}
+	if query.Since != nil && alert.CreatedAt.Before(*query.Since) {
+		return false
+	}
+	if query.Until != nil && alert.CreatedAt.After(*query.Until) {
+		return false
+	}
+	return true
+}
+
+// GetActiveAlerts 获取活动告警，包括处于等待状态的告警
 func (as *AlertingService) GetActiveAlerts() []models.Alert {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
 	var alerts []models.Alert
 	for _, alert := range as.active {
 		alerts = append(alerts, *alert)
 	}
+	for _, alert := range as.pending {
+		alerts = append(alerts, *alert)
+	}
 	return alerts
 }
 
-// AcknowledgeAlert 确认告警
-func (as *AlertingService) AcknowledgeAlert(alertID int64) error {
-	// TODO: 实现告警确认功能
-	log.Printf("Alert %d acknowledged", alertID)
+// findAlert 根据ID查找活动告警，调用方需持有锁
+func (as *AlertingService) findAlert(alertID int64) (*models.Alert, bool) {
+	for _, alert := range as.active {
+		if alert.ID == alertID {
+			return alert, true
+		}
+	}
+	return nil, false
+}
+
+// AcknowledgeAlert 确认告警，确认后告警保持活动但不再重复通知
+func (as *AlertingService) AcknowledgeAlert(alertID int64, by, comment string) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	alert, exists := as.findAlert(alertID)
+	if !exists {
+		return fmt.Errorf("active alert with ID %d not found", alertID)
+	}
+
+	now := as.now()
+	alert.AcknowledgedBy = by
+	alert.AcknowledgedAt = &now
+	alert.AckComment = comment
+
+	if as.storage != nil {
+		if err := as.storage.AcknowledgeAlert(alert); err != nil {
+			return fmt.Errorf("failed to save acknowledgement: %w", err)
+		}
+	}
+
+	as.eventMgr.EmitAlertAcknowledged(alert)
+	log.Printf("Alert %d acknowledged by %s", alertID, by)
+
 	return nil
 }
 
-// SilenceAlert 静默告警
+// SilenceAlert 静默告警，在指定时长内抑制该告警所属规则在同一目标上的通知
 func (as *AlertingService) SilenceAlert(alertID int64, duration time.Duration) error {
-	// TODO: 实现告警静默功能
-	log.Printf("Alert %d silenced for %v", alertID, duration)
-	return nil
+	if duration <= 0 {
+		return fmt.Errorf("silence duration must be positive")
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	alert, exists := as.findAlert(alertID)
+	if !exists {
+		return fmt.Errorf("active alert with ID %d not found", alertID)
+	}
+
+	_, err := as.createSilence(models.AlertSilence{
+		RuleID:   alert.RuleID,
+		Target:   alert.Target,
+		AlertID:  alertID,
+		StartsAt: as.now(),
+		EndsAt:   as.now().Add(duration),
+	})
+	return err
+}
+
+// CreateSilence 创建告警静默
+func (as *AlertingService) CreateSilence(silence models.AlertSilence) (*models.AlertSilence, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	return as.createSilence(silence)
+}
+
+// createSilence 创建告警静默，调用方需持有锁
+func (as *AlertingService) createSilence(silence models.AlertSilence) (*models.AlertSilence, error) {
+	if !as.hasRule(silence.RuleID) {
+		return nil, fmt.Errorf("rule with ID %d not found", silence.RuleID)
+	}
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return nil, fmt.Errorf("silence must end after it starts")
+	}
+
+	now := as.now()
+	silence.CreatedAt = now
+	silence.Expired = false
+
+	if as.storage != nil {
+		if err := as.storage.CreateSilence(&silence); err != nil {
+			return nil, fmt.Errorf("failed to save silence: %w", err)
+		}
+	} else {
+		as.nextID++
+		silence.ID = as.nextID
+	}
+
+	as.silences = append(as.silences, silence)
+
+	if silence.Active(now) {
+		for _, alert := range as.active {
+			if silence.Matches(alert.RuleID, alert.Target) {
+				alert.Silenced = true
+			}
+		}
+	}
+
+	as.eventMgr.EmitSilence(silence)
+	log.Printf("Alert rule %d silenced until %s", silence.RuleID, silence.EndsAt.Format(time.RFC3339))
+
+	return &silence, nil
+}
+
+// GetSilences 获取未过期的告警静默
+func (as *AlertingService) GetSilences() []models.AlertSilence {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	silences := make([]models.AlertSilence, len(as.silences))
+	copy(silences, as.silences)
+	return silences
+}
+
+// ExpireSilence 提前结束告警静默
+func (as *AlertingService) ExpireSilence(id int64) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	for i := range as.silences {
+		if as.silences[i].ID == id {
+			as.silences[i].EndsAt = as.now()
+			as.expireSilences(as.now())
+			return nil
+		}
+	}
+
+	return fmt.Errorf("silence with ID %d not found", id)
+}
+
+// expireSilences 结束已到期的静默，并恢复相关告警的通知，调用方需持有锁
+func (as *AlertingService) expireSilences(now time.Time) {
+	remaining := as.silences[:0]
+	var expired []models.AlertSilence
+
+	for _, silence := range as.silences {
+		if now.Before(silence.EndsAt) {
+			remaining = append(remaining, silence)
+			continue
+		}
+
+		silence.Expired = true
+		if as.storage != nil {
+			if err := as.storage.ExpireSilence(silence); err != nil {
+				log.Printf("Failed to expire silence %d: %v", silence.ID, err)
+			}
+		}
+		expired = append(expired, silence)
+	}
+	as.silences = remaining
+
+	for _, silence := range expired {
+		as.eventMgr.EmitSilenceExpired(silence)
+		log.Printf("Silence %d for alert rule %d expired", silence.ID, silence.RuleID)
+
+		// 静默结束后仍处于活动状态的告警需要补发通知
+		for _, alert := range as.active {
+			if silence.Matches(alert.RuleID, alert.Target) && alert.Silenced && !as.isSilenced(alert, now) {
+				alert.Silenced = false
+				as.notify(alert)
+			}
+		}
+	}
+}
+
+// isSilenced 检查告警的规则和目标当前是否处于静默状态
+func (as *AlertingService) isSilenced(alert *models.Alert, now time.Time) bool {
+	for _, silence := range as.silences {
+		if silence.Matches(alert.RuleID, alert.Target) && silence.Active(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// notify 发送告警通知，静默、已确认、维护期间、被抑制或抖动中的告警不通知，启用分组时加入分组等待发送
+func (as *AlertingService) notify(alert *models.Alert) {
+	if !as.notifiable(alert) {
+		return
+	}
+	as.markNotified(alert, true)
+
+	if as.groupWait > 0 {
+		as.enqueue(alert, notificationFiring)
+		return
+	}
+	as.send(alert, notificationFiring)
+}
+
+// notifyFired 发送本次检查中新触发告警的通知，调用方需持有锁
+func (as *AlertingService) notifyFired() {
+	for _, alert := range as.fired {
+		if alert.Status == "active" {
+			as.notify(alert)
+		}
+	}
+	as.fired = nil
+}
+
+// notifiable 检查告警当前是否可以发送触发通知，调用方需持有锁
+func (as *AlertingService) notifiable(alert *models.Alert) bool {
+	return !alert.Silenced && !alert.Suppressed && !alert.Inhibited && alert.AcknowledgedAt == nil && !as.isFlapping(alert)
+}
+
+// notifyResolved 发送告警解决通知。只要发送过触发通知就发送解决通知，
+// 即使告警已被静默、处于维护窗口或抖动中，否则外部系统中的告警会一直处于触发状态
+func (as *AlertingService) notifyResolved(alert *models.Alert) {
+	as.markNotified(alert, false)
+
+	// 还在分组中等待的触发通知不再发送
+	as.dequeueFiring(alert)
+	if alert.LastNotifiedAt == nil {
+		return
+	}
+
+	if as.groupWait > 0 {
+		as.enqueue(alert, notificationResolved)
+		return
+	}
+	as.send(alert, notificationResolved)
+}
+
+// send 立即发送告警的触发或解决通知，调用方需持有锁
+func (as *AlertingService) send(alert *models.Alert, event string) {
+	if event == notificationResolved {
+		as.eventMgr.EmitAlertResolved(alert)
+	} else {
+		as.eventMgr.EmitAlert(alert)
+	}
+	as.dispatch(alert, event)
+
+	if event == notificationFiring {
+		as.recordNotified(alert, as.now())
+	}
+}
+
+// dispatch 按规则的告警动作发送外部通知或执行修复动作，调用方需持有锁
+func (as *AlertingService) dispatch(alert *models.Alert, event string) {
+	rule, actions, ok := as.eventActions(alert, event)
+	if !ok {
+		return
+	}
+	for _, action := range actions {
+		// 修复动作在告警触发时已执行
+		if !isRemediation(action.Type) && as.notifier != nil {
+			as.notifier.Dispatch(action, rule, *alert, event)
+		}
+	}
+}
+
+// eventActions 返回发送告警事件使用的规则和动作。触发通知按升级策略选择动作并记录收到通知的渠道，
+// 解决通知发送给收到过触发通知的渠道，调用方需持有锁
+func (as *AlertingService) eventActions(alert *models.Alert, event string) (models.AlertRule, []models.AlertAction, bool) {
+	if event == notificationResolved {
+		delivery, ok := as.takeDelivery(alert)
+		return delivery.rule, delivery.actions, ok
+	}
+
+	rule, ok := as.findRule(alert.RuleID)
+	if !ok {
+		return models.AlertRule{}, nil, false
+	}
+	actions := escalationActions(rule, alert)
+	as.recordDelivery(rule, alert, actions)
+	return rule, actions, true
+}
+
+// firingDelivery 告警发送触发通知时使用的规则和通知动作
+type firingDelivery struct {
+	rule    models.AlertRule
+	actions []models.AlertAction
+}
+
+// recordDelivery 记录收到触发通知的渠道，升级后更换的渠道一并记录，调用方需持有锁
+func (as *AlertingService) recordDelivery(rule models.AlertRule, alert *models.Alert, actions []models.AlertAction) {
+	delivery, ok := as.delivered[alert]
+	if !ok {
+		delivery = &firingDelivery{}
+		as.delivered[alert] = delivery
+	}
+	delivery.rule = rule
+
+	for _, action := range actions {
+		if isRemediation(action.Type) {
+			continue
+		}
+		found := false
+		for _, sent := range delivery.actions {
+			if sent.Type == action.Type && sent.Target == action.Target {
+				found = true
+				break
+			}
+		}
+		if !found {
+			delivery.actions = append(delivery.actions, action)
+		}
+	}
+}
+
+// takeDelivery 取出告警解决通知使用的规则和渠道，重启前触发的告警没有记录，按规则当前的动作发送，调用方需持有锁
+func (as *AlertingService) takeDelivery(alert *models.Alert) (firingDelivery, bool) {
+	if delivery, ok := as.delivered[alert]; ok {
+		delete(as.delivered, alert)
+		return *delivery, true
+	}
+
+	rule, ok := as.findRule(alert.RuleID)
+	if !ok {
+		return firingDelivery{}, false
+	}
+	return firingDelivery{rule: rule, actions: escalationActions(rule, alert)}, true
+}
+
+// remediateFired 告警触发时执行规则的修复动作。每次触发只执行一次，
+// 不受静默、确认、维护窗口、抑制和升级重复通知的影响，调用方需持有锁
+func (as *AlertingService) remediateFired(alert *models.Alert) {
+	if as.remediator == nil || alert.Remediated {
+		return
+	}
+	rule, ok := as.findRule(alert.RuleID)
+	if !ok {
+		return
+	}
+
+	for _, action := range rule.Actions {
+		if isRemediation(action.Type) {
+			as.remediator.Execute(action, *alert)
+			alert.Remediated = true
+		}
+	}
+	if alert.Remediated && as.storage != nil {
+		if err := as.storage.UpdateAlertRemediated(alert); err != nil {
+			log.Printf("Failed to update alert %d: %v", alert.ID, err)
+		}
+	}
 }
 
 // GetAlertStatistics 获取告警统计
 func (as *AlertingService) GetAlertStatistics() (map[string]interface{}, error) {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
 	stats := map[string]interface{}{
-		"total_rules":    len(as.rules),
-		"active_rules":   0,
-		"active_alerts":  len(as.active),
+		"total_rules":     len(as.rules),
+		"active_rules":    0,
+		"active_alerts":   len(as.active),
+		"pending_alerts":  len(as.pending),
 		"critical_alerts": 0,
 		"warning_alerts":  0,
 		"info_alerts":     0,
@@ -355,8 +1149,20 @@ func (as *AlertingService) GetAlertStatistics() (map[string]interface{}, error)
 	return stats, nil
 }
 
-// CreateDefaultRules 创建默认告警规则
+// CreateDefaultRules 创建默认告警规则，有存储服务时仅在首次运行时创建
 func (as *AlertingService) CreateDefaultRules() error {
+	as.mu.RLock()
+	storage := as.storage
+	as.mu.RUnlock()
+
+	if storage != nil {
+		if _, seeded, err := storage.GetMeta(defaultRulesSeededKey); err != nil {
+			return fmt.Errorf("failed to check default rules: %w", err)
+		} else if seeded {
+			return nil
+		}
+	}
+
 	defaultRules := []models.AlertRule{
 		{
 			Name:      "CPU使用率过高",
@@ -399,5 +1205,11 @@ func (as *AlertingService) CreateDefaultRules() error {
 		}
 	}
 
+	if storage != nil {
+		if err := storage.SetMeta(defaultRulesSeededKey, dbTime(time.Now())); err != nil {
+			return fmt.Errorf("failed to mark default rules: %w", err)
+		}
+	}
+
 	return nil
-}
\ No newline at end of file
+}
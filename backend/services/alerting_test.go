@@ -0,0 +1,445 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// newTestAlertingService 创建使用临时数据库的告警服务
+func newTestAlertingService(t *testing.T) (*AlertingService, *StorageService) {
+	t.Helper()
+
+	storage, err := NewStorageService(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+
+	as := NewAlertingService(nil, nil)
+	as.SetStorageService(storage)
+	return as, storage
+}
+
+// testMetrics 构造告警检查使用的采集数据
+func testMetrics(cpu float64) map[string]interface{} {
+	return map[string]interface{}{
+		"cpu":    &models.CPUInfo{Usage: cpu, Load1: 1, Load5: 1, Load15: 1},
+		"memory": &models.MemoryInfo{UsedPercent: 50},
+		"disk": []models.DiskInfo{
+			{Device: "/dev/sda1", Mountpoint: "/", UsedPercent: 70},
+		},
+	}
+}
+
+func TestRulesPersistAcrossRestart(t *testing.T) {
+	as, storage := newTestAlertingService(t)
+	if err := as.CreateDefaultRules(); err != nil {
+		t.Fatalf("failed to create default rules: %v", err)
+	}
+	defaults, _ := as.GetRules()
+	if len(defaults) == 0 {
+		t.Fatal("expected default rules on first run")
+	}
+
+	custom := models.AlertRule{
+		Name:      "var full",
+		Metric:    "disk",
+		Target:    "/var",
+		Operator:  ">",
+		Threshold: 90,
+		Enabled:   true,
+		Actions:   []models.AlertAction{{Type: "webhook", Target: "https://example.com/hook", Level: "critical"}},
+	}
+	if err := as.CreateRule(custom); err != nil {
+		t.Fatalf("failed to create rule: %v", err)
+	}
+	if err := as.DeleteRule(defaults[0].ID); err != nil {
+		t.Fatalf("failed to delete rule: %v", err)
+	}
+	before, _ := as.GetRules()
+
+	// 重启后从数据库加载规则，已删除的默认规则不会重新创建
+	restarted := NewAlertingService(nil, nil)
+	restarted.SetStorageService(storage)
+	if err := restarted.LoadRules(); err != nil {
+		t.Fatalf("failed to load rules: %v", err)
+	}
+	if err := restarted.CreateDefaultRules(); err != nil {
+		t.Fatalf("failed to create default rules: %v", err)
+	}
+	after, _ := restarted.GetRules()
+
+	if len(after) != len(before) {
+		t.Fatalf("expected %d rules after restart, got %d", len(before), len(after))
+	}
+	for i := range before {
+		if after[i].ID != before[i].ID || after[i].Name != before[i].Name {
+			t.Errorf("rule %d changed after restart: %+v -> %+v", i, before[i], after[i])
+		}
+	}
+	loaded := after[len(after)-1]
+	if loaded.Name != custom.Name || loaded.Target != "/var" || len(loaded.Actions) != 1 || loaded.Actions[0] != custom.Actions[0] {
+		t.Errorf("custom rule not persisted: %+v", loaded)
+	}
+}
+
+func TestQueryAlertsHistory(t *testing.T) {
+	as, _ := newTestAlertingService(t)
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	as.now = clock.Now
+
+	for _, rule := range []models.AlertRule{
+		{Name: "cpu", Metric: "cpu", Operator: ">", Threshold: 50, Enabled: true,
+			Actions: []models.AlertAction{{Type: "webhook", Target: "ops", Level: "warning"}}},
+		{Name: "memory", Metric: "memory", Operator: ">", Threshold: 40, Enabled: true,
+			Actions: []models.AlertAction{{Type: "webhook", Target: "ops", Level: "critical"}}},
+	} {
+		if err := as.CreateRule(rule); err != nil {
+			t.Fatalf("failed to create rule: %v", err)
+		}
+	}
+
+	// cpu 告警触发、解决后再次触发，memory 告警一直处于活动状态
+	start := clock.Now()
+	for i, cpu := range []float64{90, 10, 90} {
+		if i > 0 {
+			clock.Advance(time.Minute)
+		}
+		if err := as.CheckAlerts(testMetrics(cpu)); err != nil {
+			t.Fatalf("CheckAlerts failed: %v", err)
+		}
+	}
+	refired := clock.Now()
+
+	cpuID := findRuleID(as, "cpu")
+	cases := []struct {
+		name  string
+		query models.AlertQuery
+		want  int
+	}{
+		{"all", models.AlertQuery{}, 3},
+		{"limit", models.AlertQuery{Limit: 2}, 2},
+		{"level", models.AlertQuery{Level: "critical"}, 1},
+		{"rule", models.AlertQuery{RuleID: cpuID}, 2},
+		{"resolved", models.AlertQuery{Status: "resolved"}, 1},
+		{"active", models.AlertQuery{Status: "active"}, 2},
+		{"since", models.AlertQuery{Since: &refired}, 1},
+		{"until", models.AlertQuery{Until: &start}, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			alerts, err := as.QueryAlerts(c.query)
+			if err != nil {
+				t.Fatalf("QueryAlerts failed: %v", err)
+			}
+			if len(alerts) != c.want {
+				t.Fatalf("expected %d alerts, got %+v", c.want, alerts)
+			}
+			for i := 1; i < len(alerts); i++ {
+				if alerts[i].CreatedAt.After(alerts[i-1].CreatedAt) {
+					t.Errorf("alerts not ordered by time: %+v", alerts)
+				}
+			}
+		})
+	}
+
+	resolved, _ := as.QueryAlerts(models.AlertQuery{Status: "resolved"})
+	if len(resolved) != 1 || resolved[0].RuleID != cpuID || resolved[0].ResolvedAt == nil ||
+		!resolved[0].ResolvedAt.Equal(start.Add(time.Minute)) {
+		t.Errorf("unexpected resolved alert: %+v", resolved)
+	}
+}
+
+func TestRuleDurationPendingWindow(t *testing.T) {
+	// 每分钟检查一次，规则持续时间为3分钟
+	cases := []struct {
+		name    string
+		samples []float64
+		want    []string // 每次检查后的告警状态，空字符串表示没有告警
+	}{
+		{"fires after duration", []float64{90, 90, 90, 90}, []string{"pending", "pending", "pending", "active"}},
+		{"resets on false sample", []float64{90, 90, 10, 90, 90, 90, 90}, []string{"pending", "pending", "", "pending", "pending", "pending", "active"}},
+		{"short spike", []float64{90, 10}, []string{"pending", ""}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+			as := NewAlertingService(nil, nil)
+			as.now = clock.Now
+			if err := as.CreateRule(models.AlertRule{
+				Name:      "cpu",
+				Metric:    "cpu",
+				Operator:  ">",
+				Threshold: 50,
+				Duration:  3 * time.Minute,
+				Enabled:   true,
+				Actions:   []models.AlertAction{{Type: "webhook", Target: "ops", Level: "warning"}},
+			}); err != nil {
+				t.Fatalf("failed to create rule: %v", err)
+			}
+
+			for i, cpu := range c.samples {
+				if i > 0 {
+					clock.Advance(time.Minute)
+				}
+				if err := as.CheckAlerts(testMetrics(cpu)); err != nil {
+					t.Fatalf("CheckAlerts failed: %v", err)
+				}
+
+				status := ""
+				alerts := as.GetActiveAlerts()
+				if len(alerts) > 1 {
+					t.Fatalf("check %d: expected at most one alert, got %+v", i, alerts)
+				}
+				if len(alerts) == 1 {
+					status = alerts[0].Status
+					if status == "pending" && alerts[0].PendingSince == nil {
+						t.Errorf("check %d: pending alert without PendingSince", i)
+					}
+				}
+				if status != c.want[i] {
+					t.Errorf("check %d: status %q, want %q", i, status, c.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckAlertsConcurrentRuleChanges(t *testing.T) {
+	as, _ := newTestAlertingService(t)
+
+	// 历史数据查询规则在锁外评估，与规则修改并发执行
+	seed := []models.AlertRule{
+		{Name: "cpu", Metric: "cpu", Operator: ">", Threshold: 50, Enabled: true},
+		{Name: "expr", Expression: "avg_over(cpu.usage, 5m) >= 0 || cpu.usage > 50", Enabled: true},
+		{Name: "anomaly", Type: ruleTypeAnomaly, Metric: "cpu", Enabled: true},
+		{Name: "forecast", Metric: diskForecastMetric, Operator: "<", Threshold: 24, Enabled: true},
+	}
+	for _, rule := range seed {
+		rule.Actions = []models.AlertAction{{Type: "notification", Target: "desktop", Level: "warning"}}
+		if err := as.CreateRule(rule); err != nil {
+			t.Fatalf("failed to create rule %s: %v", rule.Name, err)
+		}
+	}
+
+	const iterations = 50
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			if err := as.CheckAlerts(testMetrics(float64(i % 100))); err != nil {
+				t.Errorf("CheckAlerts failed: %v", err)
+			}
+		}
+	}()
+
+	for w := 0; w < 3; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				rule := models.AlertRule{
+					Name:      fmt.Sprintf("rule-%d-%d", w, i),
+					Metric:    "cpu",
+					Operator:  ">",
+					Threshold: float64(i),
+					Enabled:   true,
+					Actions:   []models.AlertAction{{Type: "notification", Target: "desktop", Level: "info"}},
+				}
+				if err := as.CreateRule(rule); err != nil {
+					t.Errorf("CreateRule failed: %v", err)
+					return
+				}
+
+				id := findRuleID(as, rule.Name)
+				rule.ID = id
+				rule.Threshold = float64(i + 1)
+				if err := as.UpdateRule(rule); err != nil {
+					t.Errorf("UpdateRule failed: %v", err)
+				}
+				if err := as.DeleteRule(id); err != nil {
+					t.Errorf("DeleteRule failed: %v", err)
+				}
+			}
+		}(w)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			as.GetActiveAlerts()
+			if _, err := as.GetRules(); err != nil {
+				t.Errorf("GetRules failed: %v", err)
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	rules, _ := as.GetRules()
+	if len(rules) != len(seed) {
+		t.Fatalf("expected %d rules after concurrent changes, got %d", len(seed), len(rules))
+	}
+
+	// 已删除规则的告警不应残留
+	for _, alert := range as.GetActiveAlerts() {
+		if findRuleID(as, alert.RuleName) == 0 {
+			t.Errorf("alert %s remains active after its rule was deleted", alert.RuleName)
+		}
+	}
+}
+
+// findRuleID 按名称查找规则ID，不存在时返回0
+func findRuleID(as *AlertingService, name string) int64 {
+	rules, _ := as.GetRules()
+	for _, rule := range rules {
+		if rule.Name == name {
+			return rule.ID
+		}
+	}
+	return 0
+}
+
+func TestResolveSentAfterFiringNotification(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(h *escalationHarness)
+		after func(h *escalationHarness) // 在告警触发通知发送后执行，为空时告警在下次检查中解决
+	}{
+		{
+			name: "silence then resolve",
+			after: func(h *escalationHarness) {
+				if err := h.as.SilenceAlert(h.as.GetActiveAlerts()[0].ID, time.Hour); err != nil {
+					t.Fatalf("failed to silence alert: %v", err)
+				}
+			},
+		},
+		{
+			name: "acknowledge then resolve",
+			after: func(h *escalationHarness) {
+				if err := h.as.AcknowledgeAlert(h.as.GetActiveAlerts()[0].ID, "tester", ""); err != nil {
+					t.Fatalf("failed to acknowledge alert: %v", err)
+				}
+			},
+		},
+		{
+			name: "resolve starts flapping",
+			setup: func(h *escalationHarness) {
+				rules, _ := h.as.GetRules()
+				rule := rules[0]
+				rule.Flap = &models.FlapConfig{Changes: 2, Window: time.Hour}
+				if err := h.as.UpdateRule(rule); err != nil {
+					t.Fatalf("failed to update rule: %v", err)
+				}
+			},
+		},
+		{
+			name: "delete rule",
+			after: func(h *escalationHarness) {
+				if err := h.as.DeleteRule(findRuleID(h.as, "cpu")); err != nil {
+					t.Fatalf("failed to delete rule: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := newEscalationHarness(t, nil)
+			if c.setup != nil {
+				c.setup(h)
+			}
+			if sent := h.check(0); len(sent) != 1 || sent[0].event != notificationFiring {
+				t.Fatalf("expected the firing notification, got %+v", sent)
+			}
+			if c.after != nil {
+				c.after(h)
+			}
+
+			h.clock.Advance(time.Minute)
+			if err := h.as.CheckAlerts(testMetrics(10)); err != nil {
+				t.Fatalf("CheckAlerts failed: %v", err)
+			}
+			h.ns.Wait()
+
+			sent := h.channel.snapshot()
+			if len(sent) != 2 || sent[1].event != notificationResolved || sent[1].target != "primary" {
+				t.Fatalf("expected a resolve notification to the channel that received the firing, got %+v", sent)
+			}
+		})
+	}
+}
+
+func TestSilenceMatchesRuleAndTarget(t *testing.T) {
+	as, storage := newTestAlertingService(t)
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	as.now = clock.Now
+
+	rule := models.AlertRule{
+		Name:      "disk",
+		Metric:    "disk",
+		Operator:  ">",
+		Threshold: 90,
+		Enabled:   true,
+		Actions:   []models.AlertAction{{Type: "notification", Target: "desktop", Level: "warning"}},
+	}
+	if err := as.CreateRule(rule); err != nil {
+		t.Fatalf("failed to create rule: %v", err)
+	}
+	ruleID := findRuleID(as, "disk")
+
+	data := map[string]interface{}{"disk": []models.DiskInfo{
+		{Device: "/dev/sda1", Mountpoint: "/", UsedPercent: 95},
+		{Device: "/dev/sdb1", Mountpoint: "/data", UsedPercent: 95},
+	}}
+	if err := as.CheckAlerts(data); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+
+	silenced := func() map[string]bool {
+		result := make(map[string]bool)
+		for _, alert := range as.GetActiveAlerts() {
+			result[alert.Target] = alert.Silenced
+		}
+		return result
+	}
+
+	var root models.Alert
+	for _, alert := range as.GetActiveAlerts() {
+		if alert.Target == "/" {
+			root = alert
+		}
+	}
+	if err := as.SilenceAlert(root.ID, time.Hour); err != nil {
+		t.Fatalf("failed to silence alert: %v", err)
+	}
+	if got := silenced(); !got["/"] || got["/data"] {
+		t.Fatalf("silencing / should not silence /data, got %v", got)
+	}
+
+	// 静默的目标随静默保存，重新加载后仍只作用于该目标
+	reloaded := NewAlertingService(nil, nil)
+	reloaded.SetStorageService(storage)
+	if err := reloaded.LoadRules(); err != nil {
+		t.Fatalf("failed to reload rules: %v", err)
+	}
+	if silences := reloaded.GetSilences(); len(silences) != 1 || silences[0].Target != "/" || silences[0].RuleID != ruleID {
+		t.Fatalf("unexpected silences after reload: %+v", silences)
+	}
+
+	// 不指定目标的静默作用于规则的所有目标
+	if _, err := as.CreateSilence(models.AlertSilence{RuleID: ruleID, StartsAt: clock.Now(), EndsAt: clock.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("failed to create silence: %v", err)
+	}
+	if got := silenced(); !got["/"] || !got["/data"] {
+		t.Fatalf("rule-wide silence should silence every target, got %v", got)
+	}
+}
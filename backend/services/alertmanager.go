@@ -0,0 +1,291 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"system-monitor/backend/models"
+	"system-monitor/backend/utils"
+)
+
+// alertmanagerAlertsPath Alertmanager v2 API 接收告警的路径
+const alertmanagerAlertsPath = "/api/v2/alerts"
+
+// defaultAlertmanagerResend 未配置时重新发送未解决告警的间隔
+const defaultAlertmanagerResend = time.Minute
+
+// alertmanagerTombstoneTTL 告警解决后保留记录的时长，覆盖通知重试的时间，期间到达的触发通知被忽略
+const alertmanagerTombstoneTTL = time.Hour
+
+// AlertmanagerAlert Alertmanager v2 API 的告警格式
+type AlertmanagerAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"` // 触发中的告警为预计过期时间，按重发间隔刷新
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// alertmanagerFiring 已推送的告警，解决后保留为墓碑，避免迟到或重试的触发通知重新加入
+type alertmanagerFiring struct {
+	url        string
+	alert      AlertmanagerAlert
+	resolved   bool
+	resolvedAt time.Time // 记录解决的本地时间，用于清理墓碑
+}
+
+// AlertmanagerChannel 按 Alertmanager v2 API 推送告警的通知渠道。
+// 与 Prometheus 相同，未解决的告警按间隔重新发送以刷新过期时间，应用退出后由 Alertmanager 自动解决
+type AlertmanagerChannel struct {
+	mu       sync.Mutex
+	config   utils.AlertmanagerConfig
+	client   *http.Client
+	firing   map[string]alertmanagerFiring // 按 URL/告警ID 索引，每个告警只记录一次
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewAlertmanagerChannel 创建 Alertmanager 通知渠道，并开始定期重新发送未解决的告警
+func NewAlertmanagerChannel(config utils.AlertmanagerConfig, timeout time.Duration) *AlertmanagerChannel {
+	ac := &AlertmanagerChannel{
+		config: config,
+		client: &http.Client{Timeout: timeout},
+		firing: make(map[string]alertmanagerFiring),
+		stopCh: make(chan struct{}),
+	}
+	go ac.resendLoop()
+	return ac
+}
+
+// SetConfig 更新 Alertmanager 配置，对之后发送的告警生效
+func (ac *AlertmanagerChannel) SetConfig(config utils.AlertmanagerConfig) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.config = config
+}
+
+// Stop 停止重新发送未解决的告警
+func (ac *AlertmanagerChannel) Stop() {
+	ac.stopOnce.Do(func() {
+		close(ac.stopCh)
+	})
+}
+
+// resendInterval 重新发送间隔，调用方需持有锁
+func (ac *AlertmanagerChannel) resendInterval() time.Duration {
+	if ac.config.ResendInterval > 0 {
+		return time.Duration(ac.config.ResendInterval) * time.Second
+	}
+	return defaultAlertmanagerResend
+}
+
+// Send 推送告警，target 为 Alertmanager 地址，为空时使用配置的地址。分组通知中的告警在一个请求中推送
+func (ac *AlertmanagerChannel) Send(target string, notification models.Notification) error {
+	alerts := notification.Alerts
+	if len(alerts) == 0 {
+		alerts = []models.Alert{notification.Alert}
+	}
+
+	ac.mu.Lock()
+	base := target
+	if base == "" {
+		base = ac.config.URL
+	}
+	if base == "" {
+		ac.mu.Unlock()
+		return fmt.Errorf("alertmanager URL not configured")
+	}
+	url := alertmanagerURL(base)
+	endsAt := notification.Timestamp.Add(4 * ac.resendInterval())
+
+	payload := make([]AlertmanagerAlert, 0, len(alerts))
+	keys := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		am := newAlertmanagerAlert(notification, alert, ac.config.GeneratorURL)
+		key := url + "/" + strconv.FormatInt(alert.ID, 10)
+		entry, exists := ac.firing[key]
+
+		if notification.Event == notificationResolved {
+			if am.EndsAt.IsZero() {
+				am.EndsAt = notification.Timestamp
+			}
+			ac.firing[key] = alertmanagerFiring{url: url, alert: am, resolved: true, resolvedAt: time.Now()}
+			payload = append(payload, am)
+			continue
+		}
+
+		// 告警已解决，忽略重试或迟到的触发通知
+		if exists && entry.resolved {
+			continue
+		}
+		am.EndsAt = endsAt
+		if !exists {
+			// 推送失败时也保留，由重新发送补发
+			ac.firing[key] = alertmanagerFiring{url: url, alert: am}
+		}
+		payload = append(payload, am)
+		keys = append(keys, key)
+	}
+	ac.mu.Unlock()
+
+	if len(payload) == 0 {
+		return nil
+	}
+	err := ac.post(url, payload)
+
+	// 推送触发通知期间告警被解决时，解决通知可能先于触发通知到达，重新推送解决状态
+	if notification.Event == notificationFiring {
+		ac.repostResolved(url, keys)
+	}
+	return err
+}
+
+// repostResolved 重新推送已解决的告警
+func (ac *AlertmanagerChannel) repostResolved(url string, keys []string) {
+	ac.mu.Lock()
+	var resolved []AlertmanagerAlert
+	for _, key := range keys {
+		if entry, ok := ac.firing[key]; ok && entry.resolved {
+			resolved = append(resolved, entry.alert)
+		}
+	}
+	ac.mu.Unlock()
+
+	if len(resolved) == 0 {
+		return
+	}
+	if err := ac.post(url, resolved); err != nil {
+		log.Printf("Failed to repost %d resolved alerts to alertmanager: %v", len(resolved), err)
+	}
+}
+
+// newAlertmanagerAlert 将告警转换为 Alertmanager 告警，规则名称作为 alertname 标签
+func newAlertmanagerAlert(notification models.Notification, alert models.Alert, generatorURL string) AlertmanagerAlert {
+	labels := map[string]string{
+		"alertname": alert.RuleName,
+		"severity":  alert.Level,
+		"host":      notification.Host,
+		"rule_id":   strconv.FormatInt(alert.RuleID, 10),
+	}
+	if alert.Target != "" {
+		labels["target"] = alert.Target
+	}
+	// 分组通知只携带第一个告警的规则
+	if alert.RuleID == notification.Rule.ID && notification.Rule.Metric != "" {
+		labels["metric"] = notification.Rule.Metric
+	}
+
+	summary := alert.Message
+	if len(notification.Alerts) == 0 {
+		summary = notification.Message
+	}
+
+	if generatorURL == "" {
+		generatorURL = "system-monitor://" + notification.Host
+	}
+
+	am := AlertmanagerAlert{
+		Labels: labels,
+		Annotations: map[string]string{
+			"summary":   summary,
+			"value":     strconv.FormatFloat(alert.Value, 'f', 2, 64),
+			"threshold": strconv.FormatFloat(alert.Threshold, 'f', 2, 64),
+		},
+		StartsAt:     alert.CreatedAt,
+		GeneratorURL: generatorURL,
+	}
+	if alert.ResolvedAt != nil {
+		am.EndsAt = *alert.ResolvedAt
+	}
+	return am
+}
+
+// alertmanagerURL 由 Alertmanager 地址得到接收告警的 API 地址
+func alertmanagerURL(base string) string {
+	base = strings.TrimRight(base, "/")
+	if strings.HasSuffix(base, alertmanagerAlertsPath) {
+		return base
+	}
+	return base + alertmanagerAlertsPath
+}
+
+// resendLoop 定期重新发送未解决的告警
+func (ac *AlertmanagerChannel) resendLoop() {
+	for {
+		ac.mu.Lock()
+		interval := ac.resendInterval()
+		ac.mu.Unlock()
+
+		select {
+		case <-ac.stopCh:
+			return
+		case <-time.After(interval):
+			ac.resend()
+		}
+	}
+}
+
+// resend 刷新未解决告警的过期时间并按地址重新发送，同时清理过期的墓碑
+func (ac *AlertmanagerChannel) resend() {
+	ac.mu.Lock()
+	now := time.Now()
+	endsAt := now.Add(4 * ac.resendInterval())
+	batches := make(map[string][]AlertmanagerAlert)
+	batchKeys := make(map[string][]string)
+	for key, firing := range ac.firing {
+		if firing.resolved {
+			if now.Sub(firing.resolvedAt) >= alertmanagerTombstoneTTL {
+				delete(ac.firing, key)
+			}
+			continue
+		}
+		firing.alert.EndsAt = endsAt
+		ac.firing[key] = firing
+		batches[firing.url] = append(batches[firing.url], firing.alert)
+		batchKeys[firing.url] = append(batchKeys[firing.url], key)
+	}
+	ac.mu.Unlock()
+
+	for url, alerts := range batches {
+		if err := ac.post(url, alerts); err != nil {
+			log.Printf("Failed to resend %d alerts to alertmanager: %v", len(alerts), err)
+		}
+		ac.repostResolved(url, batchKeys[url])
+	}
+}
+
+// post 发送告警到 Alertmanager，非 2xx 响应视为失败
+func (ac *AlertmanagerChannel) post(url string, alerts []AlertmanagerAlert) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("failed to encode alertmanager payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create alertmanager request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "system-monitor")
+
+	resp, err := ac.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send alerts to alertmanager: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alertmanager returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
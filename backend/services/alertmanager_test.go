@@ -0,0 +1,212 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+	"system-monitor/backend/utils"
+)
+
+// alertmanagerStub 记录收到的告警，block 不为空时阻塞第一个请求直到通道关闭
+type alertmanagerStub struct {
+	mu       sync.Mutex
+	requests [][]AlertmanagerAlert
+	status   int
+	block    chan struct{}
+	received chan struct{}
+}
+
+func newAlertmanagerStub(t *testing.T) (*alertmanagerStub, *httptest.Server) {
+	t.Helper()
+
+	stub := &alertmanagerStub{status: http.StatusOK, received: make(chan struct{}, 16)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != alertmanagerAlertsPath {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		var alerts []AlertmanagerAlert
+		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+			t.Errorf("failed to decode alerts: %v", err)
+		}
+
+		stub.mu.Lock()
+		block := stub.block
+		stub.block = nil
+		stub.mu.Unlock()
+
+		stub.received <- struct{}{}
+		if block != nil {
+			<-block
+		}
+
+		stub.mu.Lock()
+		stub.requests = append(stub.requests, alerts)
+		status := stub.status
+		stub.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return stub, server
+}
+
+// snapshot 返回收到的请求
+func (s *alertmanagerStub) snapshot() [][]AlertmanagerAlert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]AlertmanagerAlert(nil), s.requests...)
+}
+
+func newTestAlertmanagerChannel(t *testing.T, url string) *AlertmanagerChannel {
+	t.Helper()
+
+	// 重新发送间隔足够长，测试中直接调用 resend
+	ac := NewAlertmanagerChannel(utils.AlertmanagerConfig{URL: url, ResendInterval: 3600}, 5*time.Second)
+	t.Cleanup(ac.Stop)
+	return ac
+}
+
+func testAlertmanagerNotification(event string, alert models.Alert) models.Notification {
+	return models.Notification{
+		Event:     event,
+		Alert:     alert,
+		Rule:      models.AlertRule{ID: alert.RuleID, Name: alert.RuleName, Metric: "cpu"},
+		Host:      "test-host",
+		Message:   alert.Message,
+		Timestamp: time.Now(),
+	}
+}
+
+func testFiringAlert() models.Alert {
+	return models.Alert{
+		ID:        42,
+		RuleID:    7,
+		RuleName:  "CPU usage",
+		Message:   "CPU usage is high",
+		Level:     "critical",
+		Value:     95,
+		Threshold: 80,
+		Status:    "active",
+		CreatedAt: time.Now().Add(-time.Minute),
+	}
+}
+
+func resolvedCopy(alert models.Alert) models.Alert {
+	resolvedAt := time.Now()
+	alert.Status = "resolved"
+	alert.ResolvedAt = &resolvedAt
+	return alert
+}
+
+func TestAlertmanagerFiringResolveAndResend(t *testing.T) {
+	stub, server := newAlertmanagerStub(t)
+	ac := newTestAlertmanagerChannel(t, server.URL)
+	alert := testFiringAlert()
+
+	if err := ac.Send("", testAlertmanagerNotification(notificationFiring, alert)); err != nil {
+		t.Fatalf("firing send failed: %v", err)
+	}
+
+	requests := stub.snapshot()
+	if len(requests) != 1 || len(requests[0]) != 1 {
+		t.Fatalf("expected one firing alert, got %v", requests)
+	}
+	firing := requests[0][0]
+	if firing.Labels["alertname"] != "CPU usage" || firing.Labels["severity"] != "critical" || firing.Labels["rule_id"] != "7" {
+		t.Errorf("unexpected labels: %v", firing.Labels)
+	}
+	if !firing.EndsAt.After(time.Now()) {
+		t.Errorf("firing alert should expire in the future, got %v", firing.EndsAt)
+	}
+
+	// 未解决的告警按间隔重新发送
+	ac.resend()
+	if requests = stub.snapshot(); len(requests) != 2 || requests[1][0].Labels["alertname"] != "CPU usage" {
+		t.Fatalf("expected the firing alert to be resent, got %v", requests)
+	}
+
+	if err := ac.Send("", testAlertmanagerNotification(notificationResolved, resolvedCopy(alert))); err != nil {
+		t.Fatalf("resolved send failed: %v", err)
+	}
+	requests = stub.snapshot()
+	if len(requests) != 3 || requests[2][0].EndsAt.After(time.Now()) {
+		t.Fatalf("expected a resolved alert with past endsAt, got %v", requests)
+	}
+
+	// 已解决的告警不再重新发送，迟到的触发通知（如重试）也被忽略
+	ac.resend()
+	if err := ac.Send("", testAlertmanagerNotification(notificationFiring, alert)); err != nil {
+		t.Fatalf("late firing send failed: %v", err)
+	}
+	ac.resend()
+	if requests = stub.snapshot(); len(requests) != 3 {
+		t.Fatalf("expected no requests after resolve, got %d", len(requests)-3)
+	}
+}
+
+func TestAlertmanagerFailedFiringIsResent(t *testing.T) {
+	stub, server := newAlertmanagerStub(t)
+	ac := newTestAlertmanagerChannel(t, server.URL)
+	alert := testFiringAlert()
+
+	stub.status = http.StatusServiceUnavailable
+	if err := ac.Send("", testAlertmanagerNotification(notificationFiring, alert)); err == nil {
+		t.Fatal("expected an error for a 503 response")
+	}
+
+	// 重试不会重复记录告警，恢复后由重新发送补发
+	if err := ac.Send("", testAlertmanagerNotification(notificationFiring, alert)); err == nil {
+		t.Fatal("expected an error for a 503 response")
+	}
+	stub.mu.Lock()
+	stub.status = http.StatusOK
+	stub.mu.Unlock()
+
+	ac.resend()
+	requests := stub.snapshot()
+	if len(requests) != 3 || len(requests[2]) != 1 {
+		t.Fatalf("expected the failed alert to be resent once, got %v", requests)
+	}
+}
+
+func TestAlertmanagerResolveDuringFiringSend(t *testing.T) {
+	stub, server := newAlertmanagerStub(t)
+	ac := newTestAlertmanagerChannel(t, server.URL)
+	alert := testFiringAlert()
+
+	release := make(chan struct{})
+	stub.block = release
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ac.Send("", testAlertmanagerNotification(notificationFiring, alert))
+	}()
+
+	// 触发通知到达 Alertmanager 之前告警已解决
+	<-stub.received
+	if err := ac.Send("", testAlertmanagerNotification(notificationResolved, resolvedCopy(alert))); err != nil {
+		t.Fatalf("resolved send failed: %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("firing send failed: %v", err)
+	}
+
+	requests := stub.snapshot()
+	if len(requests) != 3 {
+		t.Fatalf("expected resolve, firing and reposted resolve, got %d requests", len(requests))
+	}
+	if last := requests[len(requests)-1][0]; last.EndsAt.After(time.Now()) {
+		t.Errorf("last state sent to alertmanager should be resolved, endsAt %v", last.EndsAt)
+	}
+
+	ac.resend()
+	if n := len(stub.snapshot()); n != 3 {
+		t.Errorf("resolved alert was resent, got %d requests", n)
+	}
+}
@@ -21,14 +21,28 @@ type CollectorService struct {
 	lastDisk   []models.DiskInfo
 	lastNetwork []models.NetworkInfo
 	lastProcesses []models.ProcessInfo
+	lastProcessTable []models.ProcessInfo
+
+	// 完整进程表只在有进程告警规则时收集，prevProcCPU 为上次收集时各进程的累计 CPU 时间
+	processTable bool
+	prevProcCPU  map[int32]processCPUSample
+	prevProcAt   time.Time
+
+	// 上一次采样，用于计算速率
+	prevDisk         map[string]models.DiskInfo
+	prevNetwork      map[string]models.NetworkInfo
+	lastDiskRates    []models.DiskIORate
+	lastNetworkRates []models.NetworkRate
 }
 
 // NewCollectorService 创建新的数据收集服务
 func NewCollectorService(ctx context.Context) *CollectorService {
 	return &CollectorService{
-		ctx:      ctx,
-		interval: 2 * time.Second,
-		stopCh:   make(chan struct{}),
+		ctx:         ctx,
+		interval:    2 * time.Second,
+		stopCh:      make(chan struct{}),
+		prevDisk:    make(map[string]models.DiskInfo),
+		prevNetwork: make(map[string]models.NetworkInfo),
 	}
 }
 
@@ -83,6 +97,11 @@ func (cs *CollectorService) GetDiskInfo() ([]models.DiskInfo, error) {
 		return nil, fmt.Errorf("failed to get disk info: %w", err)
 	}
 
+	// 附加I/O统计，获取失败时不影响容量信息
+	if ioStats, err := models.NewDiskIOStats(); err == nil {
+		models.AttachDiskIOStats(diskInfo, ioStats)
+	}
+
 	cs.mu.Lock()
 	cs.lastDisk = diskInfo
 	cs.mu.Unlock()
@@ -123,6 +142,83 @@ func (cs *CollectorService) GetTopProcesses(limit int) ([]models.ProcessInfo, er
 	return cs.GetProcesses("cpu", "desc", limit)
 }
 
+// processCPUSample 进程的累计 CPU 时间（秒），创建时间用于识别复用的 PID
+type processCPUSample struct {
+	total      float64
+	createTime int64
+}
+
+// SetProcessTableEnabled 设置是否收集完整进程表，关闭时丢弃上次的进程表
+func (cs *CollectorService) SetProcessTableEnabled(enabled bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.processTable = enabled
+	if !enabled {
+		cs.lastProcessTable = nil
+		cs.prevProcCPU = nil
+		cs.prevProcAt = time.Time{}
+	}
+}
+
+// GetProcessTable 获取上次收集的完整进程表，用于进程告警规则。
+// 其中的 CPUPercent 为两次收集之间的 CPU 使用率，未收集时返回 nil
+func (cs *CollectorService) GetProcessTable() []models.ProcessInfo {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.lastProcessTable
+}
+
+// collectProcesses 获取资源使用最高的 limit 个进程，启用进程表时同时收集完整进程表
+func (cs *CollectorService) collectProcesses(limit int) ([]models.ProcessInfo, error) {
+	cs.mu.RLock()
+	enabled := cs.processTable
+	cs.mu.RUnlock()
+	if !enabled {
+		return cs.GetTopProcesses(limit)
+	}
+
+	table, err := models.GetProcesses("cpu", "desc", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get processes: %w", err)
+	}
+	now := time.Now()
+
+	// 前端显示的进程列表保持原有的 CPU 使用率
+	top := append([]models.ProcessInfo(nil), table[:min(limit, len(table))]...)
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.lastProcesses = top
+	if cs.processTable {
+		cs.prevProcCPU, cs.prevProcAt = updateProcessCPU(table, cs.prevProcCPU, cs.prevProcAt, now)
+		cs.lastProcessTable = table
+	}
+
+	return top, nil
+}
+
+// updateProcessCPU 用两次收集之间累计 CPU 时间的差值计算各进程的 CPU 使用率，替换进程启动以来的平均值。
+// 首次出现的进程没有上次的采样，使用率记为 0。返回本次的采样用于下次计算
+func updateProcessCPU(table []models.ProcessInfo, prev map[int32]processCPUSample, prevAt, now time.Time) (map[int32]processCPUSample, time.Time) {
+	elapsed := now.Sub(prevAt).Seconds()
+	samples := make(map[int32]processCPUSample, len(table))
+	for i := range table {
+		proc := &table[i]
+		sample := processCPUSample{total: proc.Times.User + proc.Times.System, createTime: proc.CreateTime}
+		samples[proc.PID] = sample
+
+		proc.CPUPercent = 0
+		last, ok := prev[proc.PID]
+		if !ok || last.createTime != sample.createTime || prevAt.IsZero() || elapsed <= 0 {
+			continue
+		}
+		if delta := sample.total - last.total; delta > 0 {
+			proc.CPUPercent = delta / elapsed * 100
+		}
+	}
+	return samples, now
+}
+
 // KillProcess 终止进程
 func (cs *CollectorService) KillProcess(pid int32) error {
 	return models.KillProcess(pid)
@@ -176,7 +272,7 @@ func (cs *CollectorService) GetAllData() (map[string]interface{}, error) {
 	// 进程信息
 	go func() {
 		defer wg.Done()
-		processes, procErr = cs.GetTopProcesses(20)
+		processes, procErr = cs.collectProcesses(20)
 	}()
 
 	wg.Wait()
@@ -201,6 +297,8 @@ func (cs *CollectorService) GetAllData() (map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to get processes: %w", procErr)
 	}
 
+	diskRates, networkRates := cs.updateRates(diskInfo, networkInfo)
+
 	// 返回收集的数据
 	data := map[string]interface{}{
 		"system":   systemInfo,
@@ -208,6 +306,8 @@ func (cs *CollectorService) GetAllData() (map[string]interface{}, error) {
 		"memory":   memoryInfo,
 		"disk":     diskInfo,
 		"network":  networkInfo,
+		"disk_rates":    diskRates,
+		"network_rates": networkRates,
 		"processes": processes,
 		"timestamp": time.Now(),
 	}
@@ -215,6 +315,42 @@ func (cs *CollectorService) GetAllData() (map[string]interface{}, error) {
 	return data, nil
 }
 
+// updateRates 与上一次采样比较，计算磁盘I/O和网络速率
+func (cs *CollectorService) updateRates(disks []models.DiskInfo, nets []models.NetworkInfo) ([]models.DiskIORate, []models.NetworkRate) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	diskRates := make([]models.DiskIORate, 0, len(disks))
+	prevDisk := make(map[string]models.DiskInfo, len(disks))
+	for i := range disks {
+		current := &disks[i]
+		if previous, ok := cs.prevDisk[current.Mountpoint]; ok {
+			diskRates = append(diskRates, models.CalculateDiskIORate(current, &previous,
+				current.Timestamp.Sub(previous.Timestamp)))
+		}
+		prevDisk[current.Mountpoint] = *current
+	}
+
+	networkRates := make([]models.NetworkRate, 0, len(nets))
+	prevNetwork := make(map[string]models.NetworkInfo, len(nets))
+	for i := range nets {
+		current := &nets[i]
+		if previous, ok := cs.prevNetwork[current.Name]; ok {
+			networkRates = append(networkRates, models.CalculateNetworkRates(current, &previous,
+				current.Timestamp.Sub(previous.Timestamp)))
+		}
+		prevNetwork[current.Name] = *current
+	}
+
+	// 只保留本次仍存在的磁盘和接口
+	cs.prevDisk = prevDisk
+	cs.prevNetwork = prevNetwork
+	cs.lastDiskRates = diskRates
+	cs.lastNetworkRates = networkRates
+
+	return diskRates, networkRates
+}
+
 // GetLastData 获取上次收集的数据（用于缓存）
 func (cs *CollectorService) GetLastData() map[string]interface{} {
 	cs.mu.RLock()
@@ -225,6 +361,8 @@ func (cs *CollectorService) GetLastData() map[string]interface{} {
 		"memory":    cs.lastMemory,
 		"disk":      cs.lastDisk,
 		"network":   cs.lastNetwork,
+		"disk_rates":    cs.lastDiskRates,
+		"network_rates": cs.lastNetworkRates,
 		"processes": cs.lastProcesses,
 		"timestamp": time.Now(),
 	}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+func TestUpdateProcessCPUUsesDeltaBetweenCollections(t *testing.T) {
+	start := time.Now()
+	proc := func(pid int32, createTime int64, cpuSeconds float64) models.ProcessInfo {
+		return models.ProcessInfo{
+			PID:        pid,
+			CreateTime: createTime,
+			CPUPercent: 1, // 进程启动以来的平均值
+			Times:      models.ProcessTimes{User: cpuSeconds * 0.75, System: cpuSeconds * 0.25},
+		}
+	}
+
+	first := []models.ProcessInfo{proc(1, 100, 10), proc(2, 100, 50)}
+	prev, prevAt := updateProcessCPU(first, nil, time.Time{}, start)
+	for _, p := range first {
+		if p.CPUPercent != 0 {
+			t.Errorf("pid %d: expected 0%% without a previous sample, got %v", p.PID, p.CPUPercent)
+		}
+	}
+
+	// 2 秒内 PID 1 使用 1 秒 CPU，PID 2 被新进程复用，PID 3 首次出现
+	second := []models.ProcessInfo{proc(1, 100, 11), proc(2, 200, 60), proc(3, 300, 5)}
+	updateProcessCPU(second, prev, prevAt, start.Add(2*time.Second))
+	if got := second[0].CPUPercent; math.Abs(got-50) > 1e-9 {
+		t.Errorf("expected 50%% for pid 1, got %v", got)
+	}
+	if second[1].CPUPercent != 0 || second[2].CPUPercent != 0 {
+		t.Errorf("expected 0%% for reused and new pids, got %v and %v", second[1].CPUPercent, second[2].CPUPercent)
+	}
+}
+
+func TestUpdateRatesBetweenCollections(t *testing.T) {
+	start := time.Now()
+	disk := func(mountpoint string, at time.Time, readBytes, writeBytes, ops uint64) models.DiskInfo {
+		return models.DiskInfo{
+			Mountpoint: mountpoint,
+			Timestamp:  at,
+			IOStats:    &models.DiskIOStats{ReadBytes: readBytes, WriteBytes: writeBytes, ReadCount: ops, WriteCount: ops},
+		}
+	}
+	nic := func(name string, at time.Time, sent, recv, errs uint64) models.NetworkInfo {
+		return models.NetworkInfo{Name: name, Timestamp: at, BytesSent: sent, BytesRecv: recv, Errin: errs}
+	}
+
+	cs := &CollectorService{}
+	diskRates, networkRates := cs.updateRates(
+		[]models.DiskInfo{disk("/", start, 1000, 1000, 10)},
+		[]models.NetworkInfo{nic("eth0", start, 1000, 1000, 5), nic("eth1", start, 5000, 5000, 0)},
+	)
+	if len(diskRates) != 0 || len(networkRates) != 0 {
+		t.Fatalf("expected no rates without a previous sample, got %+v %+v", diskRates, networkRates)
+	}
+
+	// 2 秒后 eth1 计数器重置，/data 和 eth2 首次出现
+	next := start.Add(2 * time.Second)
+	diskRates, networkRates = cs.updateRates(
+		[]models.DiskInfo{disk("/", next, 5000, 3000, 20), disk("/data", next, 100, 100, 1)},
+		[]models.NetworkInfo{nic("eth0", next, 3000, 9000, 9), nic("eth1", next, 10, 10, 0), nic("eth2", next, 1, 1, 0)},
+	)
+
+	if len(diskRates) != 1 {
+		t.Fatalf("expected one disk rate, got %+v", diskRates)
+	}
+	if got := diskRates[0]; got.Mountpoint != "/" || got.ReadRate != 2000 || got.WriteRate != 1000 || got.IOPS != 10 {
+		t.Errorf("unexpected disk rate %+v", got)
+	}
+
+	cases := []struct {
+		name               string
+		tx, rx, errorsRate float64
+	}{
+		{"eth0", 1000, 4000, 2},
+		{"eth1", 0, 0, 0},
+	}
+	if len(networkRates) != len(cases) {
+		t.Fatalf("expected %d network rates, got %+v", len(cases), networkRates)
+	}
+	for i, c := range cases {
+		got := networkRates[i]
+		if got.Interface != c.name || got.TxRate != c.tx || got.RxRate != c.rx || got.ErrorsRate != c.errorsRate {
+			t.Errorf("%s: unexpected rate %+v", c.name, got)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"system-monitor/backend/models"
+	"system-monitor/backend/utils"
+)
+
+// emailDialTimeout 连接邮件服务器的超时时间
+const emailDialTimeout = 10 * time.Second
+
+// emailBatch 等待合并发送的一批通知
+type emailBatch struct {
+	notifications []models.Notification
+	done          chan struct{}
+	err           error
+}
+
+// EmailChannel 通过 SMTP 发送邮件的通知渠道，短时间内触发的告警合并为一封邮件
+type EmailChannel struct {
+	mu      sync.Mutex
+	config  utils.AlertsConfig
+	batches map[string]*emailBatch // 按收件人索引的待发送批次
+}
+
+// NewEmailChannel 创建邮件通知渠道
+func NewEmailChannel(config utils.AlertsConfig) *EmailChannel {
+	return &EmailChannel{
+		config:  config,
+		batches: make(map[string]*emailBatch),
+	}
+}
+
+// SetConfig 更新邮件配置，对之后发送的邮件生效
+func (ec *EmailChannel) SetConfig(config utils.AlertsConfig) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	ec.config = config
+}
+
+// Send 将通知加入收件人的待发送批次，并等待该批次发送完成
+func (ec *EmailChannel) Send(target string, notification models.Notification) error {
+	ec.mu.Lock()
+	config := ec.config
+	if !config.EmailEnabled {
+		ec.mu.Unlock()
+		return fmt.Errorf("email notifications are disabled")
+	}
+
+	recipient := target
+	if recipient == "" {
+		recipient = config.EmailRecipient
+	}
+	if recipient == "" {
+		ec.mu.Unlock()
+		return fmt.Errorf("email recipient not configured")
+	}
+
+	batch, ok := ec.batches[recipient]
+	if !ok {
+		batch = &emailBatch{done: make(chan struct{})}
+		ec.batches[recipient] = batch
+
+		window := time.Duration(config.SMTP.BatchWindow) * time.Second
+		time.AfterFunc(window, func() { ec.flush(recipient, batch) })
+	}
+	batch.notifications = append(batch.notifications, notification)
+	ec.mu.Unlock()
+
+	<-batch.done
+	return batch.err
+}
+
+// flush 发送收件人的一批通知
+func (ec *EmailChannel) flush(recipient string, batch *emailBatch) {
+	ec.mu.Lock()
+	if ec.batches[recipient] == batch {
+		delete(ec.batches, recipient)
+	}
+	config := ec.config.SMTP
+	notifications := batch.notifications
+	ec.mu.Unlock()
+
+	recipients := splitRecipients(recipient)
+	msg, err := buildAlertEmail(config, recipients, notifications)
+	if err == nil {
+		err = sendMail(config, recipients, msg)
+	}
+
+	batch.err = err
+	close(batch.done)
+}
+
+// SendTest 立即发送一封测试邮件，用于检查邮件服务器配置，language 为邮件使用的语言
+func (ec *EmailChannel) SendTest(recipient, language string) error {
+	ec.mu.Lock()
+	config := ec.config
+	ec.mu.Unlock()
+
+	if recipient == "" {
+		recipient = config.EmailRecipient
+	}
+	if recipient == "" {
+		return fmt.Errorf("email recipient not configured")
+	}
+
+	recipients := splitRecipients(recipient)
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	data := struct{ Host string }{host}
+	subject, err := renderBuiltin(language, templateTestSubject, data)
+	if err != nil {
+		return err
+	}
+	body, err := renderBuiltin(language, templateTestBody, data)
+	if err != nil {
+		return err
+	}
+	msg := buildEmail(config.SMTP, recipients, subject, body)
+
+	return sendMail(config.SMTP, recipients, msg)
+}
+
+// buildAlertEmail 根据一批通知生成邮件
+func buildAlertEmail(config utils.SMTPConfig, recipients []string, notifications []models.Notification) ([]byte, error) {
+	notifications = expandGroups(notifications)
+	if len(notifications) == 0 {
+		return nil, fmt.Errorf("no notifications to send")
+	}
+
+	// 同一批次的通知使用第一条通知的语言
+	first := notifications[0]
+	data := struct {
+		Host          string
+		Notifications []models.Notification
+	}{first.Host, notifications}
+
+	subject, err := renderBuiltin(first.Language, templateEmailSubject, data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := renderBuiltin(first.Language, templateEmailBody, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildEmail(config, recipients, subject, body), nil
+}
+
+// expandGroups 将分组通知展开为每个告警一条，邮件中逐条列出
+func expandGroups(notifications []models.Notification) []models.Notification {
+	expanded := make([]models.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if len(notification.Alerts) == 0 {
+			expanded = append(expanded, notification)
+			continue
+		}
+		for _, alert := range notification.Alerts {
+			n := notification
+			n.Alert = alert
+			n.Alerts = nil
+			n.Message = alert.Message
+			expanded = append(expanded, n)
+		}
+	}
+	return expanded
+}
+
+// buildEmail 生成 UTF-8 纯文本邮件
+func buildEmail(config utils.SMTPConfig, recipients []string, subject, body string) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", emailSender(config))
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: =?UTF-8?B?%s?=\r\n", base64.StdEncoding.EncodeToString([]byte(subject)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return msg.Bytes()
+}
+
+// emailSender 返回发件人地址
+func emailSender(config utils.SMTPConfig) string {
+	if config.From != "" {
+		return config.From
+	}
+	return config.Username
+}
+
+// splitRecipients 拆分逗号分隔的收件人列表
+func splitRecipients(recipient string) []string {
+	recipients := make([]string, 0)
+	for _, r := range strings.Split(recipient, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			recipients = append(recipients, r)
+		}
+	}
+	return recipients
+}
+
+// sendMail 连接 SMTP 服务器发送邮件，按配置使用 STARTTLS 和认证
+func sendMail(config utils.SMTPConfig, recipients []string, msg []byte) error {
+	if config.Host == "" {
+		return fmt.Errorf("SMTP host not configured")
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("email recipient not configured")
+	}
+
+	port := config.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(port))
+
+	conn, err := net.DialTimeout("tcp", addr, emailDialTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(2 * emailDialTimeout))
+
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if config.StartTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: config.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if config.Username != "" {
+		auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(emailSender(config)); err != nil {
+		return fmt.Errorf("SMTP MAIL command failed: %w", err)
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("SMTP RCPT command failed for %s: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA command failed: %w", err)
+	}
+	if _, err := writer.Write(msg); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return client.Quit()
+}
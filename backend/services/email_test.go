@@ -0,0 +1,245 @@
+package services
+
+import (
+	"bufio"
+	"mime"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"system-monitor/backend/models"
+	"system-monitor/backend/utils"
+)
+
+// smtpStub 最小的 SMTP 服务器，记录收到的邮件
+type smtpStub struct {
+	mu         sync.Mutex
+	messages   []string
+	recipients [][]string
+	rejectAuth bool
+	listener   net.Listener
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	stub := &smtpStub{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go stub.serve(conn)
+		}
+	}()
+	return stub
+}
+
+// serve 处理一个 SMTP 会话，不支持 STARTTLS
+func (s *smtpStub) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			conn.Write([]byte(line + "\r\n"))
+		}
+	}
+
+	reply("220 stub ESMTP")
+	var rcpts []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-stub", "250-AUTH PLAIN", "250 8BITMIME")
+		case strings.HasPrefix(cmd, "STARTTLS"):
+			reply("502 5.5.1 STARTTLS not supported")
+		case strings.HasPrefix(cmd, "AUTH"):
+			s.mu.Lock()
+			reject := s.rejectAuth
+			s.mu.Unlock()
+			if reject {
+				reply("535 5.7.8 authentication credentials invalid")
+			} else {
+				reply("235 2.7.0 authentication successful")
+			}
+		case strings.HasPrefix(cmd, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			rcpts = append(rcpts, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var msg strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				msg.WriteString(dataLine)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg.String())
+			s.recipients = append(s.recipients, rcpts)
+			s.mu.Unlock()
+			rcpts = nil
+			reply("250 OK queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// config 返回连接到桩服务器的邮件配置
+func (s *smtpStub) config(batchWindow int) utils.AlertsConfig {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return utils.AlertsConfig{
+		EmailEnabled:   true,
+		EmailRecipient: "ops@example.com, oncall@example.com",
+		SMTP: utils.SMTPConfig{
+			Host:        "127.0.0.1",
+			Port:        p,
+			From:        "monitor@example.com",
+			BatchWindow: batchWindow,
+		},
+	}
+}
+
+func (s *smtpStub) snapshot() ([]string, [][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...), append([][]string(nil), s.recipients...)
+}
+
+func emailNotification(id int64, message string) models.Notification {
+	n := testNotification(notificationFiring)
+	n.Alert.ID = id
+	n.Alert.Message = message
+	n.Message = message
+	n.Language = languageEnUS
+	return n
+}
+
+func TestEmailBatchesNotificationsWithinWindow(t *testing.T) {
+	stub := newSMTPStub(t)
+	ec := NewEmailChannel(stub.config(1))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, message := range []string{"first alert", "second alert"} {
+		wg.Add(1)
+		go func(i int, message string) {
+			defer wg.Done()
+			errs[i] = ec.Send("", emailNotification(int64(i+1), message))
+		}(i, message)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+	}
+
+	messages, recipients := stub.snapshot()
+	if len(messages) != 1 {
+		t.Fatalf("expected notifications within the batch window in one email, got %d", len(messages))
+	}
+	if !strings.Contains(messages[0], "first alert") || !strings.Contains(messages[0], "second alert") {
+		t.Errorf("email does not list both alerts:\n%s", messages[0])
+	}
+	if got := strings.Join(recipients[0], ","); got != "ops@example.com,oncall@example.com" {
+		t.Errorf("unexpected RCPT recipients %q", got)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0]))
+	if err != nil {
+		t.Fatalf("failed to parse email: %v", err)
+	}
+	if to := msg.Header.Get("To"); to != "ops@example.com, oncall@example.com" {
+		t.Errorf("unexpected To header %q", to)
+	}
+	if from := msg.Header.Get("From"); from != "monitor@example.com" {
+		t.Errorf("unexpected From header %q", from)
+	}
+
+	raw := msg.Header.Get("Subject")
+	if !strings.HasPrefix(raw, "=?UTF-8?B?") {
+		t.Errorf("subject is not RFC 2047 encoded: %q", raw)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(raw)
+	if err != nil {
+		t.Fatalf("failed to decode subject: %v", err)
+	}
+	if !strings.Contains(subject, "test-host") {
+		t.Errorf("subject %q does not name the host", subject)
+	}
+}
+
+func TestEmailSendsSeparatelyOutsideWindow(t *testing.T) {
+	stub := newSMTPStub(t)
+	ec := NewEmailChannel(stub.config(0))
+
+	for i := 0; i < 2; i++ {
+		if err := ec.Send("", emailNotification(int64(i+1), "alert")); err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+	}
+	if messages, _ := stub.snapshot(); len(messages) != 2 {
+		t.Errorf("expected 2 emails without a batch window, got %d", len(messages))
+	}
+}
+
+func TestEmailPropagatesSMTPErrors(t *testing.T) {
+	stub := newSMTPStub(t)
+
+	config := stub.config(0)
+	config.SMTP.StartTLS = true
+	ec := NewEmailChannel(config)
+	if err := ec.Send("", emailNotification(1, "alert")); err == nil || !strings.Contains(err.Error(), "failed to start TLS") {
+		t.Errorf("expected STARTTLS error, got %v", err)
+	}
+
+	stub.mu.Lock()
+	stub.rejectAuth = true
+	stub.mu.Unlock()
+
+	config = stub.config(0)
+	config.SMTP.Username = "monitor"
+	config.SMTP.Password = "wrong"
+	ec.SetConfig(config)
+	if err := ec.Send("", emailNotification(2, "alert")); err == nil || !strings.Contains(err.Error(), "SMTP authentication failed") {
+		t.Errorf("expected authentication error, got %v", err)
+	}
+
+	if messages, _ := stub.snapshot(); len(messages) != 0 {
+		t.Errorf("expected no email to be accepted, got %d", len(messages))
+	}
+
+	config.EmailEnabled = false
+	ec.SetConfig(config)
+	if err := ec.Send("", emailNotification(3, "alert")); err == nil {
+		t.Error("expected an error when email notifications are disabled")
+	}
+}
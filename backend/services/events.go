@@ -18,8 +18,11 @@ func NewEventManager(ctx context.Context) *EventManager {
 	}
 }
 
-// Emit 发送事件
+// Emit 发送事件，未绑定界面上下文时（如测试中）忽略
 func (em *EventManager) Emit(eventName string, data interface{}) {
+	if em == nil || em.ctx == nil {
+		return
+	}
 	runtime.EventsEmit(em.ctx, eventName, data)
 }
 
@@ -38,6 +41,51 @@ func (em *EventManager) EmitAlertResolved(alert interface{}) {
 	em.Emit("alert-resolved", alert)
 }
 
+// EmitAlertFlapping 发送告警抖动状态变化事件
+func (em *EventManager) EmitAlertFlapping(alert interface{}) {
+	em.Emit("alert-flapping", alert)
+}
+
+// EmitAlertEscalated 发送告警升级事件
+func (em *EventManager) EmitAlertEscalated(alert interface{}) {
+	em.Emit("alert-escalated", alert)
+}
+
+// EmitAlertGroup 发送分组告警通知事件
+func (em *EventManager) EmitAlertGroup(group interface{}) {
+	em.Emit("alert-group", group)
+}
+
+// EmitAlertAcknowledged 发送告警确认事件
+func (em *EventManager) EmitAlertAcknowledged(alert interface{}) {
+	em.Emit("alert-acknowledged", alert)
+}
+
+// EmitSilence 发送告警静默开始事件
+func (em *EventManager) EmitSilence(silence interface{}) {
+	em.Emit("alert-silenced", silence)
+}
+
+// EmitSilenceExpired 发送告警静默结束事件
+func (em *EventManager) EmitSilenceExpired(silence interface{}) {
+	em.Emit("alert-silence-expired", silence)
+}
+
+// EmitNotificationDelivery 发送通知投递结果事件
+func (em *EventManager) EmitNotificationDelivery(delivery interface{}) {
+	em.Emit("notification-delivery", delivery)
+}
+
+// EmitRemediationExecuted 发送修复动作执行结果事件
+func (em *EventManager) EmitRemediationExecuted(execution interface{}) {
+	em.Emit("remediation-executed", execution)
+}
+
+// EmitRetention 发送数据清理完成事件
+func (em *EventManager) EmitRetention(report interface{}) {
+	em.Emit("storage-retention", report)
+}
+
 // EmitError 发送错误事件
 func (em *EventManager) EmitError(err error) {
 	em.Emit("error", map[string]interface{}{
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// defaultForecastWindow 拟合磁盘增长趋势默认使用的历史时长
+const defaultForecastWindow = 6 * time.Hour
+
+// maxForecastWindow 拟合使用的最长历史时长
+const maxForecastWindow = 30 * 24 * time.Hour
+
+// 拟合所需的最少样本数和最短时间跨度，数据过少时趋势不可靠
+const (
+	forecastMinSamples = 10
+	forecastMinSpan    = 10 * time.Minute
+)
+
+// diskForecastMetric 预计写满小时数的告警指标，如 disk.hours_to_full < 24
+const diskForecastMetric = "disk.hours_to_full"
+
+// forecastCacheTTL 告警评估时磁盘预测的缓存时长
+const forecastCacheTTL = 5 * time.Minute
+
+// diskUsagePoint 磁盘使用量采样点
+type diskUsagePoint struct {
+	timestamp   int64
+	used        float64
+	free        uint64
+	usedPercent float64
+	device      string
+}
+
+// ForecastWindow 将前端传入的小时数转换为拟合时长，不大于0时使用默认值，超过上限时按上限计算
+func ForecastWindow(hours int) time.Duration {
+	if hours <= 0 {
+		return defaultForecastWindow
+	}
+	if hours > int(maxForecastWindow/time.Hour) {
+		return maxForecastWindow
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// ForecastDisks 按挂载点拟合最近一段时间的使用量趋势，估算磁盘写满时间。
+// 时长超过 rawHistoryMaxRange 时使用对应层级的汇总数据，避免读取全部原始数据
+func (s *StorageService) ForecastDisks(window time.Duration, now time.Time) ([]models.DiskForecast, error) {
+	if window <= 0 {
+		window = defaultForecastWindow
+	}
+	if window > maxForecastWindow {
+		window = maxForecastWindow
+	}
+
+	var series map[string][]diskUsagePoint
+	var err error
+	if tier := selectRollupTier(window); tier != nil {
+		series, err = s.diskUsageRollup(*tier, now.Add(-window), now)
+	} else {
+		series, err = s.diskUsageHistory(now.Add(-window))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mountpoints := make([]string, 0, len(series))
+	for mountpoint := range series {
+		mountpoints = append(mountpoints, mountpoint)
+	}
+	sort.Strings(mountpoints)
+
+	forecasts := make([]models.DiskForecast, 0, len(mountpoints))
+	for _, mountpoint := range mountpoints {
+		if forecast, ok := forecastDisk(mountpoint, series[mountpoint], now); ok {
+			forecasts = append(forecasts, forecast)
+		}
+	}
+
+	return forecasts, nil
+}
+
+// diskUsageHistory 从原始数据读取各挂载点的使用量
+func (s *StorageService) diskUsageHistory(since time.Time) (map[string][]diskUsagePoint, error) {
+	rows, err := s.db.Query(`SELECT timestamp, device, mountpoint, used_percent, free, used
+		FROM disk_history WHERE timestamp >= ? ORDER BY timestamp ASC`, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	series := make(map[string][]diskUsagePoint)
+	if err := scanDiskUsage(rows, series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// diskUsageRollup 从汇总数据读取各挂载点每个桶的平均使用量，并以最近一次原始采样作为最后一个点，
+// 反映尚未汇总的当前使用量和设备名称
+func (s *StorageService) diskUsageRollup(tier rollupTier, since, now time.Time) (map[string][]diskUsagePoint, error) {
+	rows, err := s.db.Query(fmt.Sprintf(`SELECT bucket, series,
+			COALESCE(MAX(CASE WHEN field = 'used_percent' THEN avg_value END), 0),
+			COALESCE(MAX(CASE WHEN field = 'free' THEN avg_value END), 0),
+			COALESCE(MAX(CASE WHEN field = 'used' THEN avg_value END), 0)
+		FROM %s WHERE metric = 'disk' AND field IN ('used_percent', 'free', 'used') AND bucket >= ?
+		GROUP BY bucket, series ORDER BY bucket ASC`, tier.Table), since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	series := make(map[string][]diskUsagePoint)
+	for rows.Next() {
+		var point diskUsagePoint
+		var mountpoint string
+		var free float64
+
+		if err := rows.Scan(&point.timestamp, &mountpoint, &point.usedPercent, &free, &point.used); err != nil {
+			return nil, fmt.Errorf("failed to scan disk rollup: %w", err)
+		}
+
+		point.free = uint64(free)
+		series[mountpoint] = append(series[mountpoint], point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	latest, err := s.db.Query(`SELECT d.timestamp, d.device, d.mountpoint, d.used_percent, d.free, d.used
+		FROM disk_history d JOIN (
+			SELECT mountpoint, MAX(timestamp) AS timestamp FROM disk_history WHERE timestamp >= ? GROUP BY mountpoint
+		) l ON d.mountpoint = l.mountpoint AND d.timestamp = l.timestamp`, now.Add(-rawHistoryMaxRange).Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer latest.Close()
+
+	recent := make(map[string][]diskUsagePoint)
+	if err := scanDiskUsage(latest, recent); err != nil {
+		return nil, err
+	}
+	for mountpoint, points := range recent {
+		// 同一时间戳可能有多条记录，只取一条
+		point := points[len(points)-1]
+		if existing := series[mountpoint]; len(existing) == 0 || existing[len(existing)-1].timestamp < point.timestamp {
+			series[mountpoint] = append(existing, point)
+		}
+	}
+
+	return series, nil
+}
+
+// scanDiskUsage 读取磁盘使用量记录，按挂载点追加到 series
+func scanDiskUsage(rows *sql.Rows, series map[string][]diskUsagePoint) error {
+	for rows.Next() {
+		var point diskUsagePoint
+		var mountpoint string
+		var free, used int64
+
+		if err := rows.Scan(&point.timestamp, &point.device, &mountpoint, &point.usedPercent, &free, &used); err != nil {
+			return fmt.Errorf("failed to scan disk history: %w", err)
+		}
+
+		point.free = uint64(free)
+		point.used = float64(used)
+		series[mountpoint] = append(series[mountpoint], point)
+	}
+	return rows.Err()
+}
+
+// forecastDisk 对单个挂载点做最小二乘线性拟合
+func forecastDisk(mountpoint string, points []diskUsagePoint, now time.Time) (models.DiskForecast, bool) {
+	if len(points) < forecastMinSamples {
+		return models.DiskForecast{}, false
+	}
+
+	first, last := points[0], points[len(points)-1]
+	span := time.Duration(last.timestamp-first.timestamp) * time.Second
+	if span < forecastMinSpan {
+		return models.DiskForecast{}, false
+	}
+
+	// 以第一个采样点为原点，避免时间戳过大损失精度
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		x := float64(p.timestamp - first.timestamp)
+		sumX += x
+		sumY += p.used
+		sumXY += x * p.used
+		sumXX += x * x
+	}
+
+	n := float64(len(points))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return models.DiskForecast{}, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator // 字节/秒
+
+	forecast := models.DiskForecast{
+		Mountpoint:  mountpoint,
+		Device:      last.device,
+		Used:        uint64(last.used),
+		Free:        last.free,
+		UsedPercent: last.usedPercent,
+		FillRate:    slope * 3600,
+		Samples:     len(points),
+		Window:      span.Hours(),
+		Timestamp:   now,
+	}
+
+	if slope > 0 {
+		hours := float64(last.free) / slope / 3600
+		fullAt := time.Unix(last.timestamp, 0).Add(time.Duration(hours * float64(time.Hour)))
+		forecast.HoursToFull = &hours
+		forecast.PredictedFullAt = &fullAt
+	}
+
+	return forecast, true
+}
+
+// diskForecastSamples 获取各挂载点的预计写满小时数，使用量不增长的挂载点不参与评估
+func (e *ruleEvaluator) diskForecastSamples() ([]metricSample, error) {
+	if e.storage == nil {
+		return nil, fmt.Errorf("disk forecast requires history storage")
+	}
+
+	as := e.as
+	as.cacheMu.Lock()
+	forecasts, forecastAt := as.forecasts, as.forecastAt
+	as.cacheMu.Unlock()
+
+	if forecasts == nil || e.now.Sub(forecastAt) >= forecastCacheTTL {
+		var err error
+		forecasts, err = e.storage.ForecastDisks(defaultForecastWindow, e.now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to forecast disks: %w", err)
+		}
+		as.cacheMu.Lock()
+		as.forecasts = forecasts
+		as.forecastAt = e.now
+		as.cacheMu.Unlock()
+	}
+
+	samples := make([]metricSample, 0, len(forecasts))
+	for _, forecast := range forecasts {
+		if forecast.HoursToFull == nil {
+			continue
+		}
+		samples = append(samples, metricSample{
+			Target: forecast.Mountpoint,
+			Names:  []string{forecast.Mountpoint, forecast.Device},
+			Value:  *forecast.HoursToFull,
+		})
+	}
+
+	return samples, nil
+}
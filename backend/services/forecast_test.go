@@ -0,0 +1,74 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestForecastDisksUsesRollupsForLongWindows(t *testing.T) {
+	_, storage := newTestAlertingService(t)
+	now := time.Now().Truncate(time.Minute)
+
+	// 每小时增长 1GB，总容量 100GB
+	const rate = float64(1 << 30)
+	const total = 100 * float64(1<<30)
+	usedAt := func(ts time.Time) float64 {
+		return 50*float64(1<<30) + rate*ts.Sub(now).Hours()
+	}
+
+	for m := 360; m >= 2; m-- {
+		bucket := now.Add(-time.Duration(m) * time.Minute)
+		used := usedAt(bucket)
+		for field, value := range map[string]float64{"used": used, "free": total - used, "used_percent": used / total * 100} {
+			if _, err := storage.db.Exec(`INSERT INTO metric_rollup_1m (bucket, metric, series, field, samples, min_value, avg_value, max_value, p95_value)
+				VALUES (?, 'disk', '/', ?, 30, ?, ?, ?, ?)`, bucket.Unix(), field, value, value, value, value); err != nil {
+				t.Fatalf("failed to insert rollup: %v", err)
+			}
+		}
+	}
+
+	used := usedAt(now)
+	if _, err := storage.db.Exec(`INSERT INTO disk_history (timestamp, device, mountpoint, used_percent, free, used, read_bytes, write_bytes, read_time, write_time)
+		VALUES (?, '/dev/sda1', '/', ?, ?, ?, 0, 0, 0, 0)`, now.Unix(), used/total*100, int64(total-used), int64(used)); err != nil {
+		t.Fatalf("failed to insert history: %v", err)
+	}
+
+	forecasts, err := storage.ForecastDisks(ForecastWindow(6), now)
+	if err != nil {
+		t.Fatalf("ForecastDisks failed: %v", err)
+	}
+	if len(forecasts) != 1 {
+		t.Fatalf("expected a forecast from rollup data, got %d", len(forecasts))
+	}
+
+	forecast := forecasts[0]
+	if forecast.Samples != 360 {
+		t.Errorf("expected 359 rollup buckets and the latest sample, got %d", forecast.Samples)
+	}
+	if forecast.Device != "/dev/sda1" || forecast.Used != uint64(int64(used)) {
+		t.Errorf("latest sample not used for current usage: %+v", forecast)
+	}
+	if math.Abs(forecast.FillRate-rate)/rate > 1e-6 {
+		t.Errorf("expected fill rate %v, got %v", rate, forecast.FillRate)
+	}
+	if forecast.HoursToFull == nil || math.Abs(*forecast.HoursToFull-50) > 1e-3 {
+		t.Errorf("expected 50 hours to full, got %v", forecast.HoursToFull)
+	}
+}
+
+func TestForecastWindowClamp(t *testing.T) {
+	for _, c := range []struct {
+		hours int
+		want  time.Duration
+	}{
+		{0, defaultForecastWindow},
+		{-5, defaultForecastWindow},
+		{24, 24 * time.Hour},
+		{math.MaxInt32, maxForecastWindow},
+	} {
+		if got := ForecastWindow(c.hours); got != c.want {
+			t.Errorf("ForecastWindow(%d) = %v, want %v", c.hours, got, c.want)
+		}
+	}
+}
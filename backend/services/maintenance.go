@@ -0,0 +1,391 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // Windows 等系统可能没有时区数据库
+
+	"system-monitor/backend/models"
+)
+
+// maxMaintenanceDuration 单次维护窗口的最长时长
+const maxMaintenanceDuration = 7 * 24 * time.Hour
+
+// cronDescriptors cron 表达式的简写
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// cronField cron 字段的取值范围
+type cronField struct {
+	name     string
+	min, max int
+}
+
+// cronFields 按顺序排列的 cron 字段：分 时 日 月 周
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 和 7 都表示周日
+}
+
+// cronSchedule 解析后的 cron 表达式，每个字段用位集合表示允许的取值
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool // 日或周字段为 * 时，按另一个字段匹配
+}
+
+// parseCron 解析5个字段的 cron 表达式，支持 *、列表、范围、步长和 @daily 等简写
+func parseCron(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("schedule must have %d fields (minute hour day month weekday), got %d", len(cronFields), len(parts))
+	}
+
+	bits := make([]uint64, len(parts))
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = set
+	}
+
+	// 周日可以写作 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &cronSchedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+// parseCronField 解析单个 cron 字段
+func parseCronField(part string, field cronField) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(part, ",") {
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			rangePart = item[:i]
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field: %s", field.name, item)
+			}
+			step = n
+		}
+
+		lo, hi := field.min, field.max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid %s field: %s", field.name, item)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid %s field: %s", field.name, item)
+				}
+			} else if step > 1 {
+				// 形如 5/15 表示从5开始每15个单位
+				hi = field.max
+			}
+		}
+
+		if lo < field.min || hi > field.max || lo > hi {
+			return 0, fmt.Errorf("%s field out of range (%d-%d): %s", field.name, field.min, field.max, item)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// matches 检查指定时间（分钟精度）是否匹配 cron 表达式
+func (c *cronSchedule) matches(t time.Time) bool {
+	if c.minute&(1<<uint(t.Minute())) == 0 || c.hour&(1<<uint(t.Hour())) == 0 || c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+
+	// 与标准 cron 一致：日和周都有限制时满足任一即可
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowMatch
+	case c.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// maintenanceSchedule 已解析的维护窗口
+type maintenanceSchedule struct {
+	window   models.MaintenanceWindow
+	schedule *cronSchedule
+	location *time.Location
+	starts   *scheduleStarts
+}
+
+// scheduleStarts 缓存维护窗口最近一次开始时间，之后每分钟只需检查新增的一分钟
+type scheduleStarts struct {
+	mu      sync.Mutex
+	checked time.Time // 已检查到的分钟
+	last    time.Time // 检查范围内最近一次匹配的开始时间，零值表示没有
+}
+
+// newMaintenanceSchedule 解析维护窗口的计划和时区
+func newMaintenanceSchedule(window models.MaintenanceWindow) (maintenanceSchedule, error) {
+	schedule, err := parseCron(window.Schedule)
+	if err != nil {
+		return maintenanceSchedule{}, fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	location := time.Local
+	if window.Timezone != "" {
+		if location, err = time.LoadLocation(window.Timezone); err != nil {
+			return maintenanceSchedule{}, fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+
+	return maintenanceSchedule{window: window, schedule: schedule, location: location, starts: &scheduleStarts{}}, nil
+}
+
+// active 检查维护窗口在指定时间是否生效，即最近 Duration 内是否有匹配的开始时间
+func (m maintenanceSchedule) active(now time.Time) bool {
+	if !m.window.Enabled {
+		return false
+	}
+
+	start := m.lastStart(now.In(m.location).Truncate(time.Minute))
+	return !start.IsZero() && now.Sub(start) < m.window.Duration
+}
+
+// lastStart 查找不晚于 minute 且在 Duration 范围内的最近一次开始时间。
+// 从上次检查的分钟继续向后检查，首次检查、时钟回拨或间隔超过 Duration 时才回溯整个范围
+func (m maintenanceSchedule) lastStart(minute time.Time) time.Time {
+	c := m.starts
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from := minute.Add(-m.window.Duration)
+	if !c.checked.IsZero() && !minute.Before(c.checked) && c.checked.After(from) {
+		from = c.checked
+	} else {
+		c.last = time.Time{}
+	}
+
+	for t := minute; t.After(from); t = t.Add(-time.Minute) {
+		if m.schedule.matches(t) {
+			c.last = t
+			break
+		}
+	}
+	c.checked = minute
+	return c.last
+}
+
+// appliesTo 检查维护窗口是否作用于规则
+func (m maintenanceSchedule) appliesTo(ruleID int64) bool {
+	if len(m.window.RuleIDs) == 0 {
+		return true
+	}
+	for _, id := range m.window.RuleIDs {
+		if id == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// validateMaintenanceWindow 验证维护窗口并返回解析结果
+func validateMaintenanceWindow(window models.MaintenanceWindow) (maintenanceSchedule, error) {
+	if window.Name == "" {
+		return maintenanceSchedule{}, fmt.Errorf("maintenance window name cannot be empty")
+	}
+	if window.Duration <= 0 {
+		return maintenanceSchedule{}, fmt.Errorf("maintenance window duration must be positive")
+	}
+	if window.Duration > maxMaintenanceDuration {
+		return maintenanceSchedule{}, fmt.Errorf("maintenance window duration cannot exceed %s", maxMaintenanceDuration)
+	}
+	return newMaintenanceSchedule(window)
+}
+
+// GetMaintenanceWindows 获取所有维护窗口
+func (as *AlertingService) GetMaintenanceWindows() []models.MaintenanceWindow {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	windows := make([]models.MaintenanceWindow, len(as.windows))
+	for i, m := range as.windows {
+		windows[i] = m.window
+	}
+	return windows
+}
+
+// CreateMaintenanceWindow 创建维护窗口
+func (as *AlertingService) CreateMaintenanceWindow(window models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	schedule, err := validateMaintenanceWindow(window)
+	if err != nil {
+		return models.MaintenanceWindow{}, fmt.Errorf("invalid maintenance window: %w", err)
+	}
+
+	now := time.Now()
+	window.CreatedAt = now
+	window.UpdatedAt = now
+
+	if as.storage != nil {
+		if err := as.storage.CreateMaintenanceWindow(&window); err != nil {
+			return models.MaintenanceWindow{}, fmt.Errorf("failed to save maintenance window: %w", err)
+		}
+	} else {
+		as.nextID++
+		window.ID = as.nextID
+	}
+
+	schedule.window = window
+	as.windows = append(as.windows, schedule)
+	log.Printf("Created maintenance window: %s (%s for %s)", window.Name, window.Schedule, window.Duration)
+	return window, nil
+}
+
+// UpdateMaintenanceWindow 更新维护窗口
+func (as *AlertingService) UpdateMaintenanceWindow(window models.MaintenanceWindow) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	for i, m := range as.windows {
+		if m.window.ID != window.ID {
+			continue
+		}
+
+		schedule, err := validateMaintenanceWindow(window)
+		if err != nil {
+			return fmt.Errorf("invalid maintenance window: %w", err)
+		}
+
+		window.CreatedAt = m.window.CreatedAt
+		window.UpdatedAt = time.Now()
+
+		if as.storage != nil {
+			if err := as.storage.UpdateMaintenanceWindow(window); err != nil {
+				return fmt.Errorf("failed to save maintenance window: %w", err)
+			}
+		}
+
+		schedule.window = window
+		as.windows[i] = schedule
+		log.Printf("Updated maintenance window: %s", window.Name)
+		return nil
+	}
+
+	return fmt.Errorf("maintenance window with ID %d not found", window.ID)
+}
+
+// DeleteMaintenanceWindow 删除维护窗口
+func (as *AlertingService) DeleteMaintenanceWindow(id int64) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	for i, m := range as.windows {
+		if m.window.ID != id {
+			continue
+		}
+
+		if as.storage != nil {
+			if err := as.storage.DeleteMaintenanceWindow(id); err != nil {
+				return fmt.Errorf("failed to delete maintenance window: %w", err)
+			}
+		}
+
+		as.windows = append(as.windows[:i], as.windows[i+1:]...)
+		log.Printf("Deleted maintenance window with ID: %d", id)
+		return nil
+	}
+
+	return fmt.Errorf("maintenance window with ID %d not found", id)
+}
+
+// loadMaintenanceWindows 从数据库加载维护窗口，无法解析的窗口跳过，调用方需持有锁
+func (as *AlertingService) loadMaintenanceWindows() error {
+	windows, err := as.storage.GetMaintenanceWindows()
+	if err != nil {
+		return err
+	}
+
+	as.windows = make([]maintenanceSchedule, 0, len(windows))
+	for _, window := range windows {
+		schedule, err := newMaintenanceSchedule(window)
+		if err != nil {
+			log.Printf("Skipping maintenance window %s: %v", window.Name, err)
+			continue
+		}
+		as.windows = append(as.windows, schedule)
+	}
+	return nil
+}
+
+// inMaintenance 检查规则当前是否处于维护窗口内，调用方需持有锁
+func (as *AlertingService) inMaintenance(ruleID int64, now time.Time) bool {
+	for _, m := range as.windows {
+		if m.appliesTo(ruleID) && m.active(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateMaintenance 维护窗口开始时抑制仍处于活动状态的告警的通知，窗口结束后恢复通知，
+// 抑制状态同时记录到告警历史，调用方需持有锁
+func (as *AlertingService) updateMaintenance(now time.Time) {
+	for _, alert := range as.active {
+		inMaintenance := as.inMaintenance(alert.RuleID, now)
+		if inMaintenance == alert.Suppressed {
+			continue
+		}
+
+		alert.Suppressed = inMaintenance
+		if as.storage != nil {
+			if err := as.storage.UpdateAlertSuppressed(alert); err != nil {
+				log.Printf("Failed to update alert %d: %v", alert.ID, err)
+			}
+		}
+		if inMaintenance {
+			log.Printf("Maintenance started for alert %s, notifications suppressed", alert.RuleName)
+		} else {
+			log.Printf("Maintenance ended for alert %s, notifications resumed", alert.RuleName)
+			as.notify(alert)
+		}
+	}
+}
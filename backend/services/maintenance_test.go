@@ -0,0 +1,136 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+func TestMaintenanceSuppressesActiveAlerts(t *testing.T) {
+	h := newEscalationHarness(t, nil)
+
+	if sent := h.check(0); len(sent) != 1 {
+		t.Fatalf("expected the initial notification, got %+v", sent)
+	}
+
+	// 维护窗口开始后，已触发的告警在下次检查时被抑制
+	if _, err := h.as.CreateMaintenanceWindow(models.MaintenanceWindow{
+		Name:     "deploy",
+		Schedule: "* * * * *",
+		Duration: time.Hour,
+		Timezone: "UTC",
+		Enabled:  true,
+	}); err != nil {
+		t.Fatalf("failed to create maintenance window: %v", err)
+	}
+	h.check(time.Minute)
+	if alert := h.as.GetActiveAlerts()[0]; !alert.Suppressed {
+		t.Fatal("active alert was not suppressed when maintenance started")
+	}
+
+	h.clock.Advance(time.Minute)
+	if err := h.as.CheckAlerts(testMetrics(10)); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	h.ns.Wait()
+
+	// 已收到触发通知的渠道在维护期间仍会收到解决通知
+	if sent := h.channel.snapshot(); len(sent) != 2 || sent[1].event != notificationResolved {
+		t.Errorf("expected a resolve notification during maintenance, got %+v", sent)
+	}
+}
+
+func TestMaintenancePersistsSuppressedFlag(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	as, storage := newTestAlertingService(t)
+	as.now = clock.Now
+	if err := as.CreateRule(models.AlertRule{
+		Name:      "cpu",
+		Metric:    "cpu",
+		Operator:  ">",
+		Threshold: 50,
+		Enabled:   true,
+		Actions:   []models.AlertAction{{Type: "webhook", Target: "primary"}},
+	}); err != nil {
+		t.Fatalf("failed to create rule: %v", err)
+	}
+
+	storedSuppressed := func() bool {
+		t.Helper()
+		alerts, err := storage.QueryAlerts(models.AlertQuery{Status: "active"})
+		if err != nil || len(alerts) != 1 {
+			t.Fatalf("expected one stored active alert, got %+v (%v)", alerts, err)
+		}
+		return alerts[0].Suppressed
+	}
+
+	if err := as.CheckAlerts(testMetrics(90)); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	if storedSuppressed() {
+		t.Fatal("alert fired outside maintenance was stored as suppressed")
+	}
+
+	// 维护窗口开始和结束时更新告警历史中的抑制状态
+	window, err := as.CreateMaintenanceWindow(models.MaintenanceWindow{
+		Name:     "deploy",
+		Schedule: "* * * * *",
+		Duration: time.Hour,
+		Timezone: "UTC",
+		Enabled:  true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create maintenance window: %v", err)
+	}
+	clock.Advance(time.Minute)
+	if err := as.CheckAlerts(testMetrics(90)); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	if !storedSuppressed() {
+		t.Fatal("suppressed flag was not persisted when maintenance started")
+	}
+
+	if err := as.DeleteMaintenanceWindow(window.ID); err != nil {
+		t.Fatalf("failed to delete maintenance window: %v", err)
+	}
+	clock.Advance(time.Minute)
+	if err := as.CheckAlerts(testMetrics(90)); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	if storedSuppressed() {
+		t.Error("suppressed flag was not cleared when maintenance ended")
+	}
+}
+
+func TestMaintenanceScheduleActive(t *testing.T) {
+	m, err := newMaintenanceSchedule(models.MaintenanceWindow{
+		Schedule: "0 2 * * *",
+		Duration: 90 * time.Minute,
+		Timezone: "UTC",
+		Enabled:  true,
+	})
+	if err != nil {
+		t.Fatalf("failed to parse schedule: %v", err)
+	}
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		at   time.Duration
+		want bool
+	}{
+		{time.Hour, false},
+		{2 * time.Hour, true},
+		{3 * time.Hour, true},
+		{3*time.Hour + 29*time.Minute, true},
+		{3*time.Hour + 30*time.Minute, false},
+		{26 * time.Hour, true},    // 间隔超过 Duration 后重新回溯
+		{90 * time.Minute, false}, // 时钟回拨
+		{2*time.Hour + 30*time.Second, true},
+	}
+	for _, c := range cases {
+		if got := m.active(day.Add(c.at)); got != c.want {
+			t.Errorf("active at %s = %v, want %v", day.Add(c.at).Format("15:04:05"), got, c.want)
+		}
+	}
+}
@@ -117,6 +117,9 @@ func (ms *MonitorService) monitorLoop() {
 
 // collectAndSendData 收集并发送数据
 func (ms *MonitorService) collectAndSendData() {
+	// 只有存在进程告警规则时才收集完整进程表
+	ms.collector.SetProcessTableEnabled(ms.alertingService != nil && ms.alertingService.NeedsProcessTable())
+
 	// 收集系统数据
 	data, err := ms.collector.GetAllData()
 	if err != nil {
@@ -124,6 +127,18 @@ func (ms *MonitorService) collectAndSendData() {
 		return
 	}
 
+	// 告警检查使用的数据在发送前构建，进程规则需要的完整进程表不随系统数据发送到前端
+	var alertData map[string]interface{}
+	if ms.alertingService != nil {
+		alertData = make(map[string]interface{}, len(data)+1)
+		for key, value := range data {
+			alertData[key] = value
+		}
+		if table := ms.collector.GetProcessTable(); table != nil {
+			alertData["process_table"] = table
+		}
+	}
+
 	// 发送数据到前端
 	ms.eventManager.EmitSystemData(data)
 
@@ -135,8 +150,8 @@ func (ms *MonitorService) collectAndSendData() {
 	}
 
 	// 检查告警
-	if ms.alertingService != nil {
-		if err := ms.alertingService.CheckAlerts(data); err != nil {
+	if alertData != nil {
+		if err := ms.alertingService.CheckAlerts(alertData); err != nil {
 			log.Printf("Error checking alerts: %v", err)
 		}
 	}
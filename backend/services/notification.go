@@ -0,0 +1,240 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"system-monitor/backend/models"
+	"system-monitor/backend/utils"
+)
+
+// 通知事件类型
+const (
+	notificationFiring   = "firing"
+	notificationResolved = "resolved"
+)
+
+// 通知投递状态
+const (
+	deliveryDelivered = "delivered"
+	deliveryFailed    = "failed"
+)
+
+// alertActionTypes 支持的告警动作类型，notification 为应用内通知，command 和 signal 为修复动作，其余为外部渠道
+var alertActionTypes = map[string]bool{
+	"":             true, // 未指定类型时按应用内通知处理
+	"notification": true,
+	"webhook":      true,
+	"email":        true,
+	"alertmanager": true,
+	actionCommand:  true,
+	actionSignal:   true,
+}
+
+// NotificationChannel 外部通知渠道
+type NotificationChannel interface {
+	// Send 将通知发送到目标，target 为告警动作中配置的目标，为空时使用渠道的默认目标
+	Send(target string, notification models.Notification) error
+}
+
+// NotificationService 通知服务，按告警动作的类型将通知分发到各渠道并记录投递结果
+type NotificationService struct {
+	mu            sync.RWMutex
+	storage       *StorageService
+	eventMgr      *EventManager
+	channels      map[string]NotificationChannel
+	retryAttempts int
+	retryDelay    time.Duration
+	host          string
+	language      string // 通知使用的语言
+	pending       sync.WaitGroup
+}
+
+// NewNotificationService 创建新的通知服务
+func NewNotificationService(eventMgr *EventManager) *NotificationService {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &NotificationService{
+		eventMgr:      eventMgr,
+		channels:      make(map[string]NotificationChannel),
+		retryAttempts: 3,
+		retryDelay:    2 * time.Second,
+		host:          host,
+		language:      languageZhCN,
+	}
+}
+
+// SetStorageService 设置存储服务，用于记录投递结果
+func (ns *NotificationService) SetStorageService(storage *StorageService) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.storage = storage
+}
+
+// SetRetry 设置发送失败时的重试次数和间隔
+func (ns *NotificationService) SetRetry(attempts int, delay time.Duration) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	if attempts < 1 {
+		attempts = 1
+	}
+	ns.retryAttempts = attempts
+	ns.retryDelay = delay
+}
+
+// SetLanguage 设置通知使用的语言，如 zh-CN、en-US
+func (ns *NotificationService) SetLanguage(language string) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.language = normalizeLanguage(language)
+}
+
+// RegisterChannel 注册通知渠道，name 对应告警动作的类型
+func (ns *NotificationService) RegisterChannel(name string, channel NotificationChannel) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.channels[name] = channel
+}
+
+// HasChannel 检查通知渠道是否已注册
+func (ns *NotificationService) HasChannel(name string) bool {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+	_, ok := ns.channels[name]
+	return ok
+}
+
+// Dispatch 异步发送告警通知，不阻塞告警评估
+func (ns *NotificationService) Dispatch(action models.AlertAction, rule models.AlertRule, alert models.Alert, event string) {
+	ns.mu.RLock()
+	language := ns.language
+	ns.mu.RUnlock()
+
+	notification := models.Notification{
+		Event:     event,
+		Alert:     alert,
+		Rule:      rule,
+		Host:      ns.host,
+		Message:   alert.Message,
+		Language:  language,
+		Timestamp: time.Now(),
+	}
+
+	// 告警动作配置了模板时按模板生成该渠道的消息
+	if action.Template != "" {
+		message, err := renderCustomTemplate(action.Template, newNotificationData(notification))
+		if err != nil {
+			log.Printf("Failed to render %s template of rule %s: %v", action.Type, rule.Name, err)
+		} else {
+			notification.Message = message
+		}
+	}
+
+	ns.send(action, notification)
+}
+
+// DispatchGroup 将一组告警合并为一条通知异步发送，rule 为第一个告警所属的规则
+func (ns *NotificationService) DispatchGroup(action models.AlertAction, rule models.AlertRule, alerts []models.Alert, event string) {
+	ns.mu.RLock()
+	language := ns.language
+	ns.mu.RUnlock()
+
+	data := struct {
+		Event  string
+		Alerts []models.Alert
+	}{event, alerts}
+	message, err := renderBuiltin(language, templateGroup, data)
+	if err != nil {
+		log.Printf("%v", err)
+		message = alerts[0].Message
+	}
+
+	ns.send(action, models.Notification{
+		Event:     event,
+		Alert:     alerts[0],
+		Alerts:    alerts,
+		Rule:      rule,
+		Host:      ns.host,
+		Message:   message,
+		Language:  language,
+		Timestamp: time.Now(),
+	})
+}
+
+// send 异步发送通知到告警动作对应的渠道
+func (ns *NotificationService) send(action models.AlertAction, notification models.Notification) {
+	ns.mu.RLock()
+	channel, ok := ns.channels[action.Type]
+	ns.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	ns.pending.Add(1)
+	go func() {
+		defer ns.pending.Done()
+		ns.deliver(action, channel, notification)
+	}()
+}
+
+// Wait 等待所有正在发送的通知完成
+func (ns *NotificationService) Wait() {
+	ns.pending.Wait()
+}
+
+// deliver 发送通知，失败时重试，并记录投递结果
+func (ns *NotificationService) deliver(action models.AlertAction, channel NotificationChannel, notification models.Notification) {
+	ns.mu.RLock()
+	attempts, delay, storage := ns.retryAttempts, ns.retryDelay, ns.storage
+	ns.mu.RUnlock()
+
+	tries := 0
+	err := utils.Retry(attempts, delay, func() error {
+		tries++
+		return channel.Send(action.Target, notification)
+	})
+
+	delivery := models.NotificationDelivery{
+		AlertID:   notification.Alert.ID,
+		RuleID:    notification.Rule.ID,
+		Channel:   action.Type,
+		Target:    action.Target,
+		Event:     notification.Event,
+		Status:    deliveryDelivered,
+		Attempts:  tries,
+		CreatedAt: time.Now(),
+	}
+	if err != nil {
+		delivery.Status = deliveryFailed
+		delivery.Error = err.Error()
+		log.Printf("Failed to deliver %s notification for alert %d: %v", action.Type, notification.Alert.ID, err)
+	}
+
+	if storage != nil {
+		if err := storage.InsertDelivery(&delivery); err != nil {
+			log.Printf("Failed to record notification delivery: %v", err)
+		}
+	}
+
+	if ns.eventMgr != nil {
+		ns.eventMgr.EmitNotificationDelivery(delivery)
+	}
+}
+
+// GetDeliveries 获取最近的通知投递记录
+func (ns *NotificationService) GetDeliveries(limit int) ([]models.NotificationDelivery, error) {
+	ns.mu.RLock()
+	storage := ns.storage
+	ns.mu.RUnlock()
+
+	if storage == nil {
+		return nil, fmt.Errorf("storage service not available")
+	}
+	return storage.GetDeliveries(limit)
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"system-monitor/backend/models"
+)
+
+// newTestNotificationService 创建记录投递结果到临时数据库的通知服务，失败时立即重试
+func newTestNotificationService(t *testing.T, attempts int) *NotificationService {
+	t.Helper()
+
+	storage, err := NewStorageService(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+
+	ns := NewNotificationService(nil)
+	ns.SetStorageService(storage)
+	ns.SetRetry(attempts, 0)
+	return ns
+}
+
+// flakyServer 前 failures 个请求返回 500，之后返回 200
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func dispatchWebhook(t *testing.T, ns *NotificationService, url string) []models.NotificationDelivery {
+	t.Helper()
+
+	notification := testNotification(notificationFiring)
+	action := models.AlertAction{Type: "webhook", Target: url, Level: "warning"}
+	ns.Dispatch(action, notification.Rule, notification.Alert, notificationFiring)
+	ns.Wait()
+
+	deliveries, err := ns.GetDeliveries(10)
+	if err != nil {
+		t.Fatalf("failed to get deliveries: %v", err)
+	}
+	return deliveries
+}
+
+func TestNotificationRetriesUntilDelivered(t *testing.T) {
+	ns := newTestNotificationService(t, 3)
+	ns.RegisterChannel("webhook", NewWebhookChannel("", 0))
+	server, requests := flakyServer(t, 2)
+
+	deliveries := dispatchWebhook(t, ns, server.URL)
+
+	if n := atomic.LoadInt32(requests); n != 3 {
+		t.Errorf("expected 3 requests, got %d", n)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("expected 1 delivery record, got %d", len(deliveries))
+	}
+	d := deliveries[0]
+	if d.Status != deliveryDelivered || d.Attempts != 3 || d.Error != "" {
+		t.Errorf("unexpected delivery: %+v", d)
+	}
+	if d.AlertID != 1001 || d.RuleID != 3 || d.Channel != "webhook" || d.Event != notificationFiring {
+		t.Errorf("unexpected delivery: %+v", d)
+	}
+}
+
+func TestNotificationRecordsFailedDelivery(t *testing.T) {
+	ns := newTestNotificationService(t, 2)
+	ns.RegisterChannel("webhook", NewWebhookChannel("", 0))
+	server, requests := flakyServer(t, 10)
+
+	deliveries := dispatchWebhook(t, ns, server.URL)
+
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("expected 1 delivery record, got %d", len(deliveries))
+	}
+	d := deliveries[0]
+	if d.Status != deliveryFailed || d.Attempts != 2 || d.Error == "" {
+		t.Errorf("unexpected delivery: %+v", d)
+	}
+}
+
+func TestNotificationSkipsUnregisteredChannel(t *testing.T) {
+	ns := newTestNotificationService(t, 1)
+
+	deliveries := dispatchWebhook(t, ns, "http://127.0.0.1:0")
+	if len(deliveries) != 0 {
+		t.Errorf("expected no deliveries for an unregistered channel, got %+v", deliveries)
+	}
+}
@@ -0,0 +1,385 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"system-monitor/backend/models"
+	"system-monitor/backend/utils"
+)
+
+// 修复动作类型，告警触发时执行，不参与通知分组
+const (
+	actionCommand = "command" // 执行白名单中的命令，Target 为命令名称
+	actionSignal  = "signal"  // 向进程规则 cpu/rss 告警对应的进程发送信号，Target 为信号名称
+)
+
+// 修复动作执行状态
+const (
+	remediationSucceeded = "succeeded"
+	remediationFailed    = "failed"
+	remediationRejected  = "rejected"
+)
+
+// defaultRemediationTimeout 未配置超时时命令的默认超时时间
+const defaultRemediationTimeout = 30 * time.Second
+
+// maxRemediationOutput 记录的命令输出的最大字节数
+const maxRemediationOutput = 64 * 1024
+
+// remediationSignals 修复动作可发送的信号，与 RemediationConfig.Signals 的取值一致
+var remediationSignals = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"KILL": syscall.SIGKILL,
+	"TERM": syscall.SIGTERM,
+}
+
+// processTarget 单个进程告警的目标格式：名称[PID]
+var processTarget = regexp.MustCompile(`^(.*)\[(\d+)\]$`)
+
+// isRemediation 检查告警动作是否为修复动作
+func isRemediation(actionType string) bool {
+	return actionType == actionCommand || actionType == actionSignal
+}
+
+// validateRemediation 验证规则的修复动作，信号只能发送给进程规则匹配的进程，
+// 设置了修复动作服务时还须在白名单中。修复动作在告警触发时执行一次，不能配置在升级步骤中
+func (as *AlertingService) validateRemediation(rule models.AlertRule) error {
+	if rule.Escalation != nil {
+		for _, step := range rule.Escalation.Steps {
+			for _, action := range step.Actions {
+				if isRemediation(action.Type) {
+					return fmt.Errorf("%s actions run once when the alert fires and are not allowed in escalation steps", action.Type)
+				}
+			}
+		}
+	}
+
+	for _, action := range rule.Actions {
+		if !isRemediation(action.Type) {
+			continue
+		}
+		if action.Target == "" {
+			return fmt.Errorf("%s action requires a target", action.Type)
+		}
+		// 只有按进程独立告警的条件才能确定接收信号的进程
+		if action.Type == actionSignal && (rule.Type != ruleTypeProcess || rule.Process == nil ||
+			(rule.Process.Condition != processCPU && rule.Process.Condition != processRSS)) {
+			return fmt.Errorf("signal actions are only supported for process rules with a cpu or rss condition")
+		}
+		if as.remediator != nil {
+			if err := as.remediator.ValidateAction(action); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RemediationService 修复动作服务，只执行配置文件白名单中的命令和信号，并记录每次执行的结果
+type RemediationService struct {
+	mu       sync.RWMutex
+	storage  *StorageService
+	eventMgr *EventManager
+	config   utils.RemediationConfig
+	host     string
+	pending  sync.WaitGroup
+}
+
+// NewRemediationService 创建新的修复动作服务
+func NewRemediationService(config utils.RemediationConfig, eventMgr *EventManager) *RemediationService {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &RemediationService{
+		eventMgr: eventMgr,
+		config:   config,
+		host:     host,
+	}
+}
+
+// SetStorageService 设置存储服务，用于记录执行结果
+func (rs *RemediationService) SetStorageService(storage *StorageService) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.storage = storage
+}
+
+// SetConfig 更新修复动作白名单
+func (rs *RemediationService) SetConfig(config utils.RemediationConfig) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.config = config
+}
+
+// ValidateAction 检查修复动作引用的命令或信号是否在白名单中
+func (rs *RemediationService) ValidateAction(action models.AlertAction) error {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.allowed(action)
+}
+
+// allowed 检查修复动作是否在白名单中，调用方需持有锁
+func (rs *RemediationService) allowed(action models.AlertAction) error {
+	switch action.Type {
+	case actionCommand:
+		if _, ok := rs.config.Commands[action.Target]; !ok {
+			return fmt.Errorf("command %q is not in the remediation allowlist", action.Target)
+		}
+	case actionSignal:
+		for _, name := range rs.config.Signals {
+			if name == action.Target {
+				return nil
+			}
+		}
+		return fmt.Errorf("signal %q is not in the remediation allowlist", action.Target)
+	}
+	return nil
+}
+
+// Execute 异步执行修复动作，不阻塞告警评估
+func (rs *RemediationService) Execute(action models.AlertAction, alert models.Alert) {
+	rs.pending.Add(1)
+	go func() {
+		defer rs.pending.Done()
+
+		var execution models.RemediationExecution
+		if action.Type == actionSignal {
+			execution = rs.signal(action, alert)
+		} else {
+			execution = rs.run(action, alert)
+		}
+		rs.record(&execution)
+	}()
+}
+
+// Wait 等待所有正在执行的修复动作完成
+func (rs *RemediationService) Wait() {
+	rs.pending.Wait()
+}
+
+// run 执行白名单中的命令，告警信息通过环境变量传入，超时后终止命令
+func (rs *RemediationService) run(action models.AlertAction, alert models.Alert) models.RemediationExecution {
+	execution := newRemediationExecution(action, alert)
+
+	rs.mu.RLock()
+	err := rs.allowed(action)
+	command := rs.config.Commands[action.Target]
+	timeout := time.Duration(rs.config.Timeout) * time.Second
+	rs.mu.RUnlock()
+	if err != nil {
+		return rejectRemediation(execution, err)
+	}
+
+	if command.Timeout > 0 {
+		timeout = time.Duration(command.Timeout) * time.Second
+	}
+	if timeout <= 0 {
+		timeout = defaultRemediationTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var output limitedBuffer
+	cmd := exec.CommandContext(ctx, command.Path, command.Args...)
+	cmd.Env = append(os.Environ(), rs.alertEnv(alert)...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// 命令的子进程仍持有输出管道时不无限等待
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err = cmd.Run()
+	execution.Duration = time.Since(start)
+	execution.Output = output.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		execution.Status = remediationSucceeded
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		execution.Status = remediationFailed
+		execution.ExitCode = -1
+		execution.Error = fmt.Sprintf("command timed out after %s", timeout)
+	case errors.As(err, &exitErr):
+		execution.Status = remediationFailed
+		execution.ExitCode = exitErr.ExitCode()
+		execution.Error = err.Error()
+	default:
+		execution.Status = remediationFailed
+		execution.ExitCode = -1
+		execution.Error = err.Error()
+	}
+
+	return execution
+}
+
+// alertEnv 传给修复命令的告警环境变量
+func (rs *RemediationService) alertEnv(alert models.Alert) []string {
+	env := []string{
+		"ALERT_ID=" + strconv.FormatInt(alert.ID, 10),
+		"ALERT_RULE_ID=" + strconv.FormatInt(alert.RuleID, 10),
+		"ALERT_RULE=" + alert.RuleName,
+		"ALERT_TARGET=" + alert.Target,
+		"ALERT_LEVEL=" + alert.Level,
+		"ALERT_VALUE=" + strconv.FormatFloat(alert.Value, 'f', -1, 64),
+		"ALERT_THRESHOLD=" + strconv.FormatFloat(alert.Threshold, 'f', -1, 64),
+		"ALERT_MESSAGE=" + alert.Message,
+		"ALERT_HOST=" + rs.host,
+	}
+	if m := processTarget.FindStringSubmatch(alert.Target); m != nil {
+		env = append(env, "ALERT_PID="+m[2])
+	}
+	return env
+}
+
+// signal 向告警对应的单个进程发送信号，发送前确认进程名称未变化
+func (rs *RemediationService) signal(action models.AlertAction, alert models.Alert) models.RemediationExecution {
+	execution := newRemediationExecution(action, alert)
+
+	rs.mu.RLock()
+	err := rs.allowed(action)
+	rs.mu.RUnlock()
+	if err != nil {
+		return rejectRemediation(execution, err)
+	}
+	sig, ok := remediationSignals[action.Target]
+	if !ok {
+		return rejectRemediation(execution, fmt.Errorf("unknown signal: %s", action.Target))
+	}
+
+	p, err := signalTarget(alert)
+	if err == nil {
+		execution.PID = p.Pid
+		start := time.Now()
+		err = p.SendSignal(sig)
+		execution.Duration = time.Since(start)
+	}
+	if err != nil {
+		execution.Status = remediationFailed
+		execution.ExitCode = -1
+		execution.Error = err.Error()
+	} else {
+		execution.Status = remediationSucceeded
+	}
+	return execution
+}
+
+// signalTarget 由告警目标 名称[PID] 查找接收信号的进程，不会发送给规则匹配的其他进程
+func signalTarget(alert models.Alert) (*process.Process, error) {
+	m := processTarget.FindStringSubmatch(alert.Target)
+	if m == nil {
+		return nil, fmt.Errorf("alert target %q does not identify a process", alert.Target)
+	}
+	pid, err := strconv.ParseInt(m[2], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid process target %q: %w", alert.Target, err)
+	}
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return nil, fmt.Errorf("process %d is no longer running", pid)
+	}
+	// PID 可能已被其他进程复用
+	if name, err := p.Name(); err != nil || name != m[1] {
+		return nil, fmt.Errorf("process %d is no longer running", pid)
+	}
+	return p, nil
+}
+
+// newRemediationExecution 创建告警的修复动作执行记录
+func newRemediationExecution(action models.AlertAction, alert models.Alert) models.RemediationExecution {
+	return models.RemediationExecution{
+		AlertID:   alert.ID,
+		RuleID:    alert.RuleID,
+		Action:    action.Type,
+		Target:    action.Target,
+		CreatedAt: time.Now(),
+	}
+}
+
+// rejectRemediation 将执行记录标记为因不在白名单中而拒绝执行
+func rejectRemediation(execution models.RemediationExecution, err error) models.RemediationExecution {
+	execution.Status = remediationRejected
+	execution.ExitCode = -1
+	execution.Error = err.Error()
+	return execution
+}
+
+// record 保存执行结果并通知前端
+func (rs *RemediationService) record(execution *models.RemediationExecution) {
+	if execution.Status == remediationSucceeded {
+		log.Printf("Remediation %s %s for alert %d succeeded", execution.Action, execution.Target, execution.AlertID)
+	} else {
+		log.Printf("Remediation %s %s for alert %d %s: %s", execution.Action, execution.Target, execution.AlertID,
+			execution.Status, execution.Error)
+	}
+
+	rs.mu.RLock()
+	storage := rs.storage
+	rs.mu.RUnlock()
+
+	if storage != nil {
+		if err := storage.InsertRemediation(execution); err != nil {
+			log.Printf("Failed to record remediation execution: %v", err)
+		}
+	}
+
+	if rs.eventMgr != nil {
+		rs.eventMgr.EmitRemediationExecuted(*execution)
+	}
+}
+
+// GetExecutions 获取最近的修复动作执行记录
+func (rs *RemediationService) GetExecutions(limit int) ([]models.RemediationExecution, error) {
+	rs.mu.RLock()
+	storage := rs.storage
+	rs.mu.RUnlock()
+
+	if storage == nil {
+		return nil, fmt.Errorf("storage service not available")
+	}
+	return storage.GetRemediations(limit)
+}
+
+// limitedBuffer 只保留前 maxRemediationOutput 字节的输出缓冲区
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	truncated bool
+}
+
+// Write 写入输出，超出长度的部分丢弃但不返回错误，避免命令因管道写入失败而退出
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := maxRemediationOutput - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// String 返回记录的输出，截断时附加标记
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "\n... (output truncated)"
+	}
+	return b.buf.String()
+}
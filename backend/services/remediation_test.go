@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+	"system-monitor/backend/utils"
+)
+
+func TestSignalActionsRequirePerProcessConditions(t *testing.T) {
+	as, _ := newTestAlertingService(t)
+
+	for _, c := range []struct {
+		condition string
+		ok        bool
+	}{
+		{processPresent, false},
+		{processAbsent, false},
+		{processCount, false},
+		{processCPU, true},
+		{processRSS, true},
+	} {
+		rule := models.AlertRule{
+			Type:    ruleTypeProcess,
+			Process: &models.ProcessConfig{Name: "worker", Condition: c.condition},
+			Actions: []models.AlertAction{{Type: actionSignal, Target: "TERM"}},
+		}
+		if err := as.validateRemediation(rule); (err == nil) != c.ok {
+			t.Errorf("condition %s: expected ok=%v, got %v", c.condition, c.ok, err)
+		}
+	}
+}
+
+func TestSignalOnlyTargetsAlertProcess(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("sleep not available: %v", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	t.Cleanup(func() { cmd.Process.Kill() })
+
+	rs := NewRemediationService(utils.RemediationConfig{Signals: []string{"TERM"}}, nil)
+	action := models.AlertAction{Type: actionSignal, Target: "TERM"}
+
+	// 没有 名称[PID] 目标的告警不重新匹配进程
+	if execution := rs.signal(action, models.Alert{Target: ""}); execution.Status != remediationFailed || execution.PID != 0 {
+		t.Errorf("expected failure without a process target, got %+v", execution)
+	}
+
+	// PID 对应的进程名称不一致时视为已被复用
+	if execution := rs.signal(action, models.Alert{Target: fmt.Sprintf("other[%d]", cmd.Process.Pid)}); execution.Status != remediationFailed {
+		t.Errorf("expected failure for a reused PID, got %+v", execution)
+	}
+	select {
+	case <-exited:
+		t.Fatal("process was signalled for a mismatched target")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	execution := rs.signal(action, models.Alert{Target: fmt.Sprintf("sleep[%d]", cmd.Process.Pid)})
+	if execution.Status != remediationSucceeded || execution.PID != int32(cmd.Process.Pid) {
+		t.Fatalf("expected the alert process to be signalled, got %+v", execution)
+	}
+	select {
+	case err := <-exited:
+		if err == nil || !strings.Contains(err.Error(), "terminated") {
+			t.Errorf("expected the process to be terminated, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("process did not exit after SIGTERM")
+	}
+}
+
+func TestRemediationRunsOncePerFiring(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy *models.EscalationPolicy
+		setup  func(h *escalationHarness)
+	}{
+		{"notified", nil, nil},
+		{"repeated notifications", &models.EscalationPolicy{RepeatInterval: time.Minute}, nil},
+		{"silenced", nil, func(h *escalationHarness) {
+			ruleID := findRuleID(h.as, "cpu")
+			if _, err := h.as.CreateSilence(models.AlertSilence{RuleID: ruleID, StartsAt: h.clock.Now(), EndsAt: h.clock.Now().Add(time.Hour)}); err != nil {
+				h.t.Fatalf("failed to create silence: %v", err)
+			}
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+			channel := &recordingChannel{}
+			ns := NewNotificationService(nil)
+			ns.RegisterChannel("webhook", channel)
+
+			as, storage := newTestAlertingService(t)
+			as.now = clock.Now
+			as.SetNotificationService(ns)
+			rs := NewRemediationService(utils.RemediationConfig{
+				Commands: map[string]utils.RemediationCommand{"restart": {Path: "true"}},
+			}, nil)
+			rs.SetStorageService(storage)
+			as.SetRemediationService(rs)
+
+			rule := models.AlertRule{
+				Name:       "cpu",
+				Metric:     "cpu",
+				Operator:   ">",
+				Threshold:  50,
+				Enabled:    true,
+				Escalation: c.policy,
+				Actions: []models.AlertAction{
+					{Type: "webhook", Target: "primary", Level: "warning"},
+					{Type: actionCommand, Target: "restart"},
+				},
+			}
+			if err := as.CreateRule(rule); err != nil {
+				t.Fatalf("failed to create rule: %v", err)
+			}
+			h := &escalationHarness{t: t, as: as, ns: ns, channel: channel, clock: clock}
+			if c.setup != nil {
+				c.setup(h)
+			}
+
+			// 告警持续期间的重复通知不会再次执行修复动作
+			for i := 0; i < 3; i++ {
+				h.check(time.Minute)
+			}
+			rs.Wait()
+
+			alerts := h.as.GetActiveAlerts()
+			if len(alerts) != 1 || !alerts[0].Remediated {
+				t.Fatalf("expected one remediated alert, got %+v", alerts)
+			}
+			executions, err := storage.GetRemediations(10)
+			if err != nil {
+				t.Fatalf("failed to get remediations: %v", err)
+			}
+			if len(executions) != 1 || executions[0].AlertID != alerts[0].ID {
+				t.Fatalf("expected one remediation for alert %d, got %+v", alerts[0].ID, executions)
+			}
+			stored, err := storage.QueryAlerts(models.AlertQuery{Status: "active"})
+			if err != nil || len(stored) != 1 || !stored[0].Remediated {
+				t.Errorf("expected the remediated flag to be persisted, got %+v (%v)", stored, err)
+			}
+		})
+	}
+}
+
+func TestRemediationNotAllowedInEscalationSteps(t *testing.T) {
+	as := NewAlertingService(nil, nil)
+	rule := models.AlertRule{
+		Escalation: &models.EscalationPolicy{Steps: []models.EscalationStep{
+			{After: time.Minute, Actions: []models.AlertAction{{Type: actionCommand, Target: "restart"}}},
+		}},
+	}
+	if err := as.validateRemediation(rule); err == nil {
+		t.Error("expected remediation actions in escalation steps to be rejected")
+	}
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// rawHistoryTables 原始历史数据表
+var rawHistoryTables = []string{"cpu_history", "memory_history", "disk_history", "network_history"}
+
+// RetentionPolicy 数据保留策略
+type RetentionPolicy struct {
+	Raw     time.Duration            // 原始历史数据保留时长
+	Rollups map[string]time.Duration // 汇总层级名称 -> 保留时长
+	Alerts  time.Duration            // 已结束告警记录和通知投递记录保留时长
+}
+
+// TableRetention 单个表的清理结果
+type TableRetention struct {
+	Table       string    `json:"table"`
+	Cutoff      time.Time `json:"cutoff"`
+	RowsDeleted int64     `json:"rows_deleted"`
+}
+
+// RetentionReport 数据清理报告
+type RetentionReport struct {
+	Tables         []TableRetention `json:"tables"`
+	RowsDeleted    int64            `json:"rows_deleted"`
+	BytesReclaimed int64            `json:"bytes_reclaimed"`
+	Duration       time.Duration    `json:"duration"`
+	Timestamp      time.Time        `json:"timestamp"`
+}
+
+// retentionRule 单个表的清理规则
+type retentionRule struct {
+	table  string
+	maxAge time.Duration
+	query  string // 删除语句，唯一参数为截止时间
+	cutoff func(t time.Time) interface{}
+}
+
+// unixCutoff 以Unix时间戳作为截止参数
+func unixCutoff(t time.Time) interface{} {
+	return t.Unix()
+}
+
+// datetimeCutoff 以SQLite DATETIME格式作为截止参数
+func datetimeCutoff(t time.Time) interface{} {
+	return dbTime(t)
+}
+
+// rules 展开为逐表的清理规则，保留时长不大于0的表不清理
+func (p RetentionPolicy) rules() []retentionRule {
+	var rules []retentionRule
+
+	if p.Raw > 0 {
+		for _, table := range rawHistoryTables {
+			rules = append(rules, retentionRule{
+				table:  table,
+				maxAge: p.Raw,
+				query:  fmt.Sprintf("DELETE FROM %s WHERE timestamp < ?", table),
+				cutoff: unixCutoff,
+			})
+		}
+	}
+
+	for _, tier := range rollupTiers {
+		maxAge := p.Rollups[tier.Name]
+		if maxAge <= 0 {
+			continue
+		}
+		rules = append(rules, retentionRule{
+			table:  tier.Table,
+			maxAge: maxAge,
+			query:  fmt.Sprintf("DELETE FROM %s WHERE bucket < ?", tier.Table),
+			cutoff: unixCutoff,
+		})
+	}
+
+	if p.Alerts > 0 {
+		// 仍处于活动状态的告警不清理
+		rules = append(rules, retentionRule{
+			table:  "alerts",
+			maxAge: p.Alerts,
+			query:  "DELETE FROM alerts WHERE status != 'active' AND created_at < ?",
+			cutoff: datetimeCutoff,
+		}, retentionRule{
+			table:  "notification_deliveries",
+			maxAge: p.Alerts,
+			query:  "DELETE FROM notification_deliveries WHERE created_at < ?",
+			cutoff: datetimeCutoff,
+		}, retentionRule{
+			table:  "remediation_executions",
+			maxAge: p.Alerts,
+			query:  "DELETE FROM remediation_executions WHERE created_at < ?",
+			cutoff: datetimeCutoff,
+		})
+	}
+
+	return rules
+}
+
+// enableIncrementalVacuum 启用增量清理模式。旧数据库需要执行一次完整VACUUM才能生效，
+// 耗时与数据库大小成正比，因此在后台执行；未完成时下次启动会重新执行
+func (s *StorageService) enableIncrementalVacuum() error {
+	var mode int
+	if err := s.db.QueryRow("PRAGMA auto_vacuum").Scan(&mode); err != nil {
+		return err
+	}
+
+	// 2 表示 INCREMENTAL
+	if mode == 2 {
+		return nil
+	}
+
+	size, err := s.databaseSize()
+	if err != nil {
+		return err
+	}
+
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+
+		// 关闭存储服务时中断 VACUUM，下次启动重新执行
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-s.stopCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		start := time.Now()
+		log.Printf("Converting database to incremental auto vacuum (%d bytes), this may take a while", size)
+		if err := s.vacuumIncremental(ctx); err != nil {
+			log.Printf("Failed to enable incremental vacuum: %v", err)
+			return
+		}
+		log.Printf("Database converted to incremental auto vacuum in %v", time.Since(start))
+	}()
+
+	return nil
+}
+
+// vacuumIncremental 设置增量清理模式并执行完整VACUUM
+func (s *StorageService) vacuumIncremental(ctx context.Context) error {
+	// auto_vacuum 与 VACUUM 必须在同一连接上执行
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA auto_vacuum = INCREMENTAL"); err != nil {
+		return err
+	}
+	_, err = conn.ExecContext(ctx, "VACUUM")
+	return err
+}
+
+// incrementalVacuum 回收空闲页，每次step只释放一页，需要遍历全部结果
+func (s *StorageService) incrementalVacuum() error {
+	rows, err := s.db.Query("PRAGMA incremental_vacuum")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+// databaseSize 获取数据库文件占用的字节数
+func (s *StorageService) databaseSize() (int64, error) {
+	var pageCount, pageSize int64
+	if err := s.db.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, err
+	}
+	if err := s.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, err
+	}
+	return pageCount * pageSize, nil
+}
+
+// RunRetention 按保留策略清理各表数据，并执行增量VACUUM回收空间
+func (s *StorageService) RunRetention(policy RetentionPolicy, now time.Time) (*RetentionReport, error) {
+	start := time.Now()
+	report := &RetentionReport{
+		Tables:    make([]TableRetention, 0),
+		Timestamp: now,
+	}
+
+	sizeBefore, err := s.databaseSize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database size: %w", err)
+	}
+
+	for _, rule := range policy.rules() {
+		cutoff := now.Add(-rule.maxAge)
+
+		result, err := s.db.Exec(rule.query, rule.cutoff(cutoff))
+		if err != nil {
+			return nil, fmt.Errorf("failed to cleanup %s: %w", rule.table, err)
+		}
+
+		deleted, _ := result.RowsAffected()
+		report.Tables = append(report.Tables, TableRetention{
+			Table:       rule.table,
+			Cutoff:      cutoff,
+			RowsDeleted: deleted,
+		})
+		report.RowsDeleted += deleted
+	}
+
+	if err := s.incrementalVacuum(); err != nil {
+		return nil, fmt.Errorf("failed to run incremental vacuum: %w", err)
+	}
+
+	sizeAfter, err := s.databaseSize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database size: %w", err)
+	}
+
+	if sizeBefore > sizeAfter {
+		report.BytesReclaimed = sizeBefore - sizeAfter
+	}
+	report.Duration = time.Since(start)
+
+	return report, nil
+}
+
+// StartRetention 启动定时数据清理任务，清理结果通过事件管理器上报
+func (s *StorageService) StartRetention(policy RetentionPolicy, interval time.Duration, eventMgr *EventManager) {
+	s.retentionMu.Lock()
+	defer s.retentionMu.Unlock()
+
+	s.retentionPolicy = policy
+	s.retentionInterval = interval
+	s.retentionReset = s.startJob("retention", interval, func(now time.Time) {
+		s.retentionMu.Lock()
+		policy := s.retentionPolicy
+		s.retentionMu.Unlock()
+
+		report, err := s.RunRetention(policy, now)
+		if err != nil {
+			log.Printf("Error enforcing history retention: %v", err)
+			if eventMgr != nil {
+				eventMgr.EmitError(err)
+			}
+			return
+		}
+
+		log.Printf("History retention: deleted %d rows, reclaimed %d bytes",
+			report.RowsDeleted, report.BytesReclaimed)
+
+		if eventMgr != nil {
+			eventMgr.EmitRetention(report)
+		}
+	})
+}
+
+// SetRetention 更新数据保留策略和清理间隔，策略在下次清理时生效，间隔变化时重置定时器
+func (s *StorageService) SetRetention(policy RetentionPolicy, interval time.Duration) {
+	s.retentionMu.Lock()
+	defer s.retentionMu.Unlock()
+
+	s.retentionPolicy = policy
+	if interval <= 0 || interval == s.retentionInterval {
+		return
+	}
+	s.retentionInterval = interval
+	if s.retentionReset == nil {
+		return
+	}
+
+	// 丢弃尚未生效的旧间隔，只保留最新值
+	select {
+	case <-s.retentionReset:
+	default:
+	}
+	s.retentionReset <- interval
+}
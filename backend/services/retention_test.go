@@ -0,0 +1,86 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+func TestRunRetentionPerTable(t *testing.T) {
+	storage, err := NewStorageService(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	old, recent := now.Add(-10*24*time.Hour), now.Add(-time.Hour)
+
+	for _, ts := range []time.Time{old, old, recent} {
+		if err := storage.exec(`INSERT INTO cpu_history (timestamp, usage_percent, load1, load5, load15) VALUES (?, 10, 1, 1, 1)`,
+			ts.Unix()); err != nil {
+			t.Fatalf("failed to insert cpu history: %v", err)
+		}
+	}
+	for _, ts := range []time.Time{old, recent} {
+		if err := storage.exec(`INSERT INTO metric_rollup_1m (bucket, metric, field, samples, min_value, avg_value, max_value, p95_value)
+			VALUES (?, 'cpu', 'usage', 1, 1, 1, 1, 1)`, ts.Unix()); err != nil {
+			t.Fatalf("failed to insert rollup: %v", err)
+		}
+	}
+
+	// 旧的已解决告警被清理，仍处于活动状态的旧告警保留
+	for _, alert := range []models.Alert{
+		{RuleName: "old resolved", Status: "resolved", CreatedAt: old},
+		{RuleName: "old active", Status: "active", CreatedAt: old},
+		{RuleName: "recent resolved", Status: "resolved", CreatedAt: recent},
+	} {
+		if err := storage.InsertAlert(&alert); err != nil {
+			t.Fatalf("failed to insert alert: %v", err)
+		}
+	}
+
+	report, err := storage.RunRetention(RetentionPolicy{
+		Raw:     7 * 24 * time.Hour,
+		Rollups: map[string]time.Duration{"1m": 24 * time.Hour, "1h": 0},
+		Alerts:  7 * 24 * time.Hour,
+	}, now)
+	if err != nil {
+		t.Fatalf("RunRetention failed: %v", err)
+	}
+
+	deleted := make(map[string]int64)
+	for _, table := range report.Tables {
+		deleted[table.Table] = table.RowsDeleted
+	}
+	cases := []struct {
+		table    string
+		deleted  int64
+		included bool
+	}{
+		{"cpu_history", 2, true},
+		{"memory_history", 0, true},
+		{"metric_rollup_1m", 1, true},
+		{"metric_rollup_1h", 0, false}, // 保留时长为0的层级不清理
+		{"alerts", 1, true},
+	}
+	for _, c := range cases {
+		got, ok := deleted[c.table]
+		if ok != c.included || got != c.deleted {
+			t.Errorf("%s: deleted %d (included %v), want %d (included %v)", c.table, got, ok, c.deleted, c.included)
+		}
+	}
+	if report.RowsDeleted != 4 {
+		t.Errorf("expected 4 rows deleted in total, got %d", report.RowsDeleted)
+	}
+
+	alerts, err := storage.QueryAlerts(models.AlertQuery{})
+	if err != nil {
+		t.Fatalf("failed to query alerts: %v", err)
+	}
+	if len(alerts) != 2 || alerts[0].RuleName != "recent resolved" || alerts[1].RuleName != "old active" {
+		t.Errorf("unexpected remaining alerts: %+v", alerts)
+	}
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// rollupTier 历史数据汇总层级
+type rollupTier struct {
+	Name  string
+	Table string
+	Width time.Duration
+	// MaxRange 该层级适用的最大查询时长，超出后使用更粗的层级
+	MaxRange time.Duration
+}
+
+// rollupTiers 按粒度从细到粗排列的汇总层级
+var rollupTiers = []rollupTier{
+	{Name: "1m", Table: "metric_rollup_1m", Width: time.Minute, MaxRange: 24 * time.Hour},
+	{Name: "15m", Table: "metric_rollup_15m", Width: 15 * time.Minute, MaxRange: 7 * 24 * time.Hour},
+	{Name: "1h", Table: "metric_rollup_1h", Width: time.Hour, MaxRange: 0},
+}
+
+// rawHistoryMaxRange 查询时长不超过该值时直接读取原始数据
+const rawHistoryMaxRange = time.Hour
+
+// rollupGrace 构建汇总时为刚写入的原始数据预留的时间
+const rollupGrace = 10 * time.Second
+
+// rollupWindow 单次查询原始数据的最大时间窗口，避免一次性加载过多行
+const rollupWindow = 24 * time.Hour
+
+// rollupField 需要汇总的原始数据列
+type rollupField struct {
+	Column string // 原始表中的列名
+	Key    string // 返回结果中的字段名
+}
+
+// rollupSource 汇总的数据来源
+type rollupSource struct {
+	Metric    string
+	Table     string
+	SeriesCol string // 区分序列的列（挂载点、网络接口），为空表示单一序列
+	SeriesKey string
+	Fields    []rollupField
+}
+
+// rollupSources 各指标的汇总定义
+var rollupSources = []rollupSource{
+	{
+		Metric: "cpu",
+		Table:  "cpu_history",
+		Fields: []rollupField{
+			{Column: "usage_percent", Key: "usage"},
+			{Column: "load1", Key: "load1"},
+			{Column: "load5", Key: "load5"},
+			{Column: "load15", Key: "load15"},
+		},
+	},
+	{
+		Metric: "memory",
+		Table:  "memory_history",
+		Fields: []rollupField{
+			{Column: "used_percent", Key: "used_percent"},
+			{Column: "swap_percent", Key: "swap_percent"},
+			{Column: "available", Key: "available"},
+			{Column: "free", Key: "free"},
+			{Column: "used", Key: "used"},
+			{Column: "swap_used", Key: "swap_used"},
+		},
+	},
+	{
+		Metric:    "disk",
+		Table:     "disk_history",
+		SeriesCol: "mountpoint",
+		SeriesKey: "mountpoint",
+		Fields: []rollupField{
+			{Column: "used_percent", Key: "used_percent"},
+			{Column: "free", Key: "free"},
+			{Column: "used", Key: "used"},
+			{Column: "read_bytes", Key: "read_bytes"},
+			{Column: "write_bytes", Key: "write_bytes"},
+			{Column: "read_time", Key: "read_time"},
+			{Column: "write_time", Key: "write_time"},
+		},
+	},
+	{
+		Metric:    "network",
+		Table:     "network_history",
+		SeriesCol: "interface",
+		SeriesKey: "interface",
+		Fields: []rollupField{
+			{Column: "bytes_sent", Key: "bytes_sent"},
+			{Column: "bytes_recv", Key: "bytes_recv"},
+			{Column: "packet_sent", Key: "packet_sent"},
+			{Column: "packet_recv", Key: "packet_recv"},
+		},
+	},
+}
+
+// findRollupSource 根据指标名称查找汇总定义
+func findRollupSource(metric string) (rollupSource, bool) {
+	for _, src := range rollupSources {
+		if src.Metric == metric {
+			return src, true
+		}
+	}
+	return rollupSource{}, false
+}
+
+// selectRollupTier 根据查询时长选择合适的汇总层级，返回nil表示使用原始数据
+func selectRollupTier(duration time.Duration) *rollupTier {
+	if duration <= rawHistoryMaxRange {
+		return nil
+	}
+
+	for i := range rollupTiers {
+		if rollupTiers[i].MaxRange == 0 || duration <= rollupTiers[i].MaxRange {
+			return &rollupTiers[i]
+		}
+	}
+
+	return &rollupTiers[len(rollupTiers)-1]
+}
+
+// initRollupTables 初始化汇总表
+func (s *StorageService) initRollupTables() error {
+	for _, tier := range rollupTiers {
+		if err := s.exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			bucket INTEGER NOT NULL,
+			metric TEXT NOT NULL,
+			series TEXT NOT NULL DEFAULT '',
+			field TEXT NOT NULL,
+			samples INTEGER NOT NULL,
+			min_value REAL NOT NULL,
+			avg_value REAL NOT NULL,
+			max_value REAL NOT NULL,
+			p95_value REAL NOT NULL,
+			PRIMARY KEY (bucket, metric, series, field)
+		)`, tier.Table)); err != nil {
+			return err
+		}
+
+		if err := s.exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_metric_bucket ON %s(metric, bucket)",
+			tier.Table, tier.Table)); err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// StartRollups 启动后台汇总任务
+func (s *StorageService) StartRollups(interval time.Duration) {
+	s.startJob("rollup", interval, func(now time.Time) {
+		if err := s.RunRollups(now); err != nil {
+			log.Printf("Error building history rollups: %v", err)
+		}
+	})
+}
+
+// RunRollups 将已完成时间桶内的原始数据汇总到各层级
+func (s *StorageService) RunRollups(now time.Time) error {
+	for _, tier := range rollupTiers {
+		if err := s.buildTier(tier, now); err != nil {
+			return fmt.Errorf("failed to build %s rollup: %w", tier.Name, err)
+		}
+	}
+	return nil
+}
+
+// buildTier 构建单个层级的汇总数据
+func (s *StorageService) buildTier(tier rollupTier, now time.Time) error {
+	width := int64(tier.Width / time.Second)
+	end := alignBucket(now.Add(-rollupGrace).Unix(), width)
+
+	start, ok, err := s.nextRollupStart(tier)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	start = alignBucket(start, width)
+
+	window := int64(rollupWindow / time.Second)
+	for from := start; from < end; from += window {
+		to := from + window
+		if to > end {
+			to = end
+		}
+
+		for _, src := range rollupSources {
+			if err := s.rollupRange(tier, src, from, to); err != nil {
+				return fmt.Errorf("%s: %w", src.Metric, err)
+			}
+		}
+
+		// 没有原始数据的区间也推进水位，避免之后重复查询
+		if err := s.SetMeta(rollupWatermarkKey(tier), strconv.FormatInt(to, 10)); err != nil {
+			return fmt.Errorf("failed to save watermark: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// rollupWatermarkKey 层级已构建到的时间点在应用元数据中的键
+func rollupWatermarkKey(tier rollupTier) string {
+	return "rollup_watermark_" + tier.Name
+}
+
+// nextRollupStart 获取层级下一个待构建时间桶的起点，优先使用保存的水位
+func (s *StorageService) nextRollupStart(tier rollupTier) (int64, bool, error) {
+	value, ok, err := s.GetMeta(rollupWatermarkKey(tier))
+	if err != nil {
+		return 0, false, err
+	}
+	if ok {
+		watermark, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid watermark %q: %w", value, err)
+		}
+		return watermark, true, nil
+	}
+
+	// 没有水位的旧数据库从已有汇总之后继续构建
+	var last sql.NullInt64
+	if err := s.db.QueryRow(fmt.Sprintf("SELECT MAX(bucket) FROM %s", tier.Table)).Scan(&last); err != nil {
+		return 0, false, err
+	}
+	if last.Valid {
+		return last.Int64 + int64(tier.Width/time.Second), true, nil
+	}
+
+	// 首次构建时从最早的原始数据开始
+	var earliest sql.NullInt64
+	for _, src := range rollupSources {
+		var ts sql.NullInt64
+		if err := s.db.QueryRow(fmt.Sprintf("SELECT MIN(timestamp) FROM %s", src.Table)).Scan(&ts); err != nil {
+			return 0, false, err
+		}
+		if ts.Valid && (!earliest.Valid || ts.Int64 < earliest.Int64) {
+			earliest = ts
+		}
+	}
+
+	return earliest.Int64, earliest.Valid, nil
+}
+
+// rollupKey 汇总分组键
+type rollupKey struct {
+	bucket int64
+	series string
+	field  string
+}
+
+// rollupRange 汇总 [from, to) 区间内的原始数据
+func (s *StorageService) rollupRange(tier rollupTier, src rollupSource, from, to int64) error {
+	width := int64(tier.Width / time.Second)
+
+	seriesCol := "''"
+	if src.SeriesCol != "" {
+		seriesCol = src.SeriesCol
+	}
+
+	columns := ""
+	for _, f := range src.Fields {
+		columns += ", " + f.Column
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf("SELECT timestamp, %s%s FROM %s WHERE timestamp >= ? AND timestamp < ?",
+		seriesCol, columns, src.Table), from, to)
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[rollupKey][]float64)
+	for rows.Next() {
+		var timestamp int64
+		var series string
+		values := make([]float64, len(src.Fields))
+
+		dest := []interface{}{&timestamp, &series}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			continue
+		}
+
+		bucket := alignBucket(timestamp, width)
+		for i, f := range src.Fields {
+			key := rollupKey{bucket: bucket, series: series, field: f.Key}
+			groups[key] = append(groups[key], values[i])
+		}
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]rollupKey, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+
+	return s.execBatch(fmt.Sprintf(`INSERT OR REPLACE INTO %s (bucket, metric, series, field, samples, min_value, avg_value, max_value, p95_value)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, tier.Table), len(keys), func(i int) []interface{} {
+		key := keys[i]
+		min, avg, max, p95 := summarize(groups[key])
+		return []interface{}{key.bucket, src.Metric, key.series, key.field, len(groups[key]), min, avg, max, p95}
+	})
+}
+
+// getRollupHistory 从汇总层级读取历史数据
+func (s *StorageService) getRollupHistory(tier rollupTier, src rollupSource, duration time.Duration) ([]map[string]interface{}, error) {
+	since := time.Now().Add(-duration).Unix()
+
+	rows, err := s.db.Query(fmt.Sprintf(`SELECT bucket, series, field, samples, min_value, avg_value, max_value, p95_value
+		FROM %s WHERE metric = ? AND bucket >= ? ORDER BY bucket ASC, series ASC`, tier.Table), src.Metric, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type pointKey struct {
+		bucket int64
+		series string
+	}
+
+	var results []map[string]interface{}
+	index := make(map[pointKey]map[string]interface{})
+	for rows.Next() {
+		var bucket int64
+		var series, field string
+		var samples int64
+		var min, avg, max, p95 float64
+
+		if err := rows.Scan(&bucket, &series, &field, &samples, &min, &avg, &max, &p95); err != nil {
+			continue
+		}
+
+		key := pointKey{bucket: bucket, series: series}
+		point, exists := index[key]
+		if !exists {
+			point = map[string]interface{}{
+				"timestamp":  time.Unix(bucket, 0),
+				"resolution": tier.Name,
+				"samples":    samples,
+			}
+			if src.SeriesKey != "" {
+				point[src.SeriesKey] = series
+			}
+			index[key] = point
+			results = append(results, point)
+		}
+
+		point[field] = avg
+		point[field+"_min"] = min
+		point[field+"_max"] = max
+		point[field+"_p95"] = p95
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s rollup history: %w", tier.Name, err)
+	}
+
+	return results, nil
+}
+
+// alignBucket 将时间戳向下对齐到时间桶起点
+func alignBucket(timestamp, width int64) int64 {
+	return timestamp - timestamp%width
+}
+
+// summarize 计算最小值、平均值、最大值和95分位数
+func summarize(values []float64) (min, avg, max, p95 float64) {
+	if len(values) == 0 {
+		return 0, 0, 0, 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+
+	// 最近秩法计算分位数
+	rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return sorted[0], sum / float64(len(sorted)), sorted[len(sorted)-1], sorted[rank]
+}
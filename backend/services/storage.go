@@ -2,26 +2,45 @@ package services
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
 	"time"
 
+	"system-monitor/backend/models"
+
 	_ "modernc.org/sqlite"
 )
 
+// sqliteBusyTimeout 并发写入时等待数据库锁的最长时间（毫秒），超时后才返回 database is locked
+const sqliteBusyTimeout = 5000
+
 // StorageService 数据存储服务
 type StorageService struct {
-	db *sql.DB
+	db       *sql.DB
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	jobs     sync.WaitGroup
+
+	retentionMu       sync.Mutex
+	retentionPolicy   RetentionPolicy
+	retentionInterval time.Duration
+	retentionReset    chan time.Duration // 向清理任务发送新的执行间隔
 }
 
 // NewStorageService 创建新的存储服务
 func NewStorageService(dbPath string) (*StorageService, error) {
-	db, err := sql.Open("sqlite", dbPath)
+	// 汇总、清理、告警和通知投递会并发写入，每个连接都需要设置忙等待
+	db, err := sql.Open("sqlite", fmt.Sprintf("%s?_pragma=busy_timeout(%d)", dbPath, sqliteBusyTimeout))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	storage := &StorageService{
-		db: db,
+		db:     db,
+		stopCh: make(chan struct{}),
 	}
 
 	// 初始化数据库表
@@ -30,6 +49,12 @@ func NewStorageService(dbPath string) (*StorageService, error) {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
+	// 启用增量清理，便于数据清理后回收磁盘空间。建表完成后再在后台转换，避免与建表争用数据库锁
+	if err := storage.enableIncrementalVacuum(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to enable incremental vacuum: %w", err)
+	}
+
 	return storage, nil
 }
 
@@ -112,6 +137,16 @@ func (s *StorageService) initTables() error {
 		return err
 	}
 
+	// 历史数据汇总表
+	if err := s.initRollupTables(); err != nil {
+		return err
+	}
+
+	// 告警规则表
+	if err := s.initAlertTables(); err != nil {
+		return err
+	}
+
 	// 创建索引
 	indexes := []string{
 		"CREATE INDEX IF NOT EXISTS idx_cpu_history_timestamp ON cpu_history(timestamp)",
@@ -120,6 +155,7 @@ func (s *StorageService) initTables() error {
 		"CREATE INDEX IF NOT EXISTS idx_network_history_timestamp ON network_history(timestamp)",
 		"CREATE INDEX IF NOT EXISTS idx_alerts_created_at ON alerts(created_at)",
 		"CREATE INDEX IF NOT EXISTS idx_alerts_status ON alerts(status)",
+		"CREATE INDEX IF NOT EXISTS idx_alerts_rule_id ON alerts(rule_id)",
 	}
 
 	for _, idx := range indexes {
@@ -143,6 +179,34 @@ func (s *StorageService) exec(query string, args ...interface{}) error {
 	return err
 }
 
+// execBatch 在同一事务中对n组参数执行同一条SQL语句
+func (s *StorageService) execBatch(query string, n int, args func(i int) []interface{}) error {
+	if n == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for i := 0; i < n; i++ {
+		if _, err := stmt.Exec(args(i)...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // StoreHistoryData 存储历史数据
 func (s *StorageService) StoreHistoryData(data map[string]interface{}) error {
 	timestamp := time.Now().Unix()
@@ -180,36 +244,90 @@ func (s *StorageService) StoreHistoryData(data map[string]interface{}) error {
 
 // storeCPUHistory 存储CPU历史数据
 func (s *StorageService) storeCPUHistory(timestamp int64, data interface{}) error {
-	// 这里需要根据实际的数据结构来解析
-	// 暂时使用简单的实现
-	return s.exec(`INSERT INTO cpu_history (timestamp, usage_percent, load1, load5, load15)
-		VALUES (?, ?, ?, ?, ?)`,
-		timestamp, 0.0, 0.0, 0.0, 0.0)
+	cpuInfo, ok := data.(*models.CPUInfo)
+	if !ok || cpuInfo == nil {
+		return fmt.Errorf("unexpected CPU data type: %T", data)
+	}
+
+	// 每核使用率以JSON数组形式存储
+	perCore, err := json.Marshal(cpuInfo.UsagePerCore)
+	if err != nil {
+		return fmt.Errorf("failed to marshal per-core usage: %w", err)
+	}
+
+	return s.exec(`INSERT INTO cpu_history (timestamp, usage_percent, load1, load5, load15, per_core)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		timestamp, cpuInfo.Usage, cpuInfo.Load1, cpuInfo.Load5, cpuInfo.Load15, string(perCore))
 }
 
 // storeMemoryHistory 存储内存历史数据
 func (s *StorageService) storeMemoryHistory(timestamp int64, data interface{}) error {
+	memInfo, ok := data.(*models.MemoryInfo)
+	if !ok || memInfo == nil {
+		return fmt.Errorf("unexpected memory data type: %T", data)
+	}
+
 	return s.exec(`INSERT INTO memory_history (timestamp, used_percent, swap_percent, available, free, used, swap_used)
 		VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		timestamp, 0.0, 0.0, 0, 0, 0, 0)
+		timestamp, memInfo.UsedPercent, memInfo.SwapPercent,
+		int64(memInfo.Available), int64(memInfo.Free), int64(memInfo.Used), int64(memInfo.SwapUsed))
 }
 
-// storeDiskHistory 存储磁盘历史数据
+// storeDiskHistory 存储磁盘历史数据，每个挂载点一行
 func (s *StorageService) storeDiskHistory(timestamp int64, data interface{}) error {
-	return s.exec(`INSERT INTO disk_history (timestamp, device, mountpoint, used_percent, free, used, read_bytes, write_bytes, read_time, write_time)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		timestamp, "", "", 0.0, 0, 0, 0, 0, 0, 0)
+	disks, ok := data.([]models.DiskInfo)
+	if !ok {
+		return fmt.Errorf("unexpected disk data type: %T", data)
+	}
+
+	return s.execBatch(`INSERT INTO disk_history (timestamp, device, mountpoint, used_percent, free, used, read_bytes, write_bytes, read_time, write_time)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, len(disks), func(i int) []interface{} {
+		disk := disks[i]
+
+		// 没有I/O统计的分区记为0
+		var readBytes, writeBytes, readTime, writeTime uint64
+		if disk.IOStats != nil {
+			readBytes = disk.IOStats.ReadBytes
+			writeBytes = disk.IOStats.WriteBytes
+			readTime = disk.IOStats.ReadTime
+			writeTime = disk.IOStats.WriteTime
+		}
+
+		return []interface{}{
+			timestamp, disk.Device, disk.Mountpoint, disk.UsedPercent,
+			int64(disk.Free), int64(disk.Used),
+			int64(readBytes), int64(writeBytes), int64(readTime), int64(writeTime),
+		}
+	})
 }
 
-// storeNetworkHistory 存储网络历史数据
+// storeNetworkHistory 存储网络历史数据，每个网络接口一行
 func (s *StorageService) storeNetworkHistory(timestamp int64, data interface{}) error {
-	return s.exec(`INSERT INTO network_history (timestamp, interface, bytes_sent, bytes_recv, packet_sent, packet_recv)
-		VALUES (?, ?, ?, ?, ?, ?)`,
-		timestamp, "", 0, 0, 0, 0)
+	nets, ok := data.([]models.NetworkInfo)
+	if !ok {
+		return fmt.Errorf("unexpected network data type: %T", data)
+	}
+
+	return s.execBatch(`INSERT INTO network_history (timestamp, interface, bytes_sent, bytes_recv, packet_sent, packet_recv)
+		VALUES (?, ?, ?, ?, ?, ?)`, len(nets), func(i int) []interface{} {
+		iface := nets[i]
+		return []interface{}{
+			timestamp, iface.Name,
+			int64(iface.BytesSent), int64(iface.BytesRecv),
+			int64(iface.PacketsSent), int64(iface.PacketsRecv),
+		}
+	})
 }
 
-// GetHistoryData 获取历史数据
+// GetHistoryData 获取历史数据，duration 单位为分钟
 func (s *StorageService) GetHistoryData(metric string, duration int) (interface{}, error) {
+	// 较长时间范围使用汇总数据，避免扫描大量原始数据
+	if tier := selectRollupTier(time.Duration(duration) * time.Minute); tier != nil {
+		if src, ok := findRollupSource(metric); ok {
+			return s.getRollupHistory(*tier, src, time.Duration(duration)*time.Minute)
+		}
+	}
+
 	// 根据metric查询相应的历史数据
 	switch metric {
 	case "cpu":
@@ -229,7 +347,7 @@ func (s *StorageService) GetHistoryData(metric string, duration int) (interface{
 func (s *StorageService) getCPUHistory(duration int) ([]map[string]interface{}, error) {
 	since := time.Now().Add(-time.Duration(duration) * time.Minute).Unix()
 
-	rows, err := s.db.Query(`SELECT timestamp, usage_percent, load1, load5, load15
+	rows, err := s.db.Query(`SELECT timestamp, usage_percent, load1, load5, load15, per_core
 		FROM cpu_history WHERE timestamp >= ? ORDER BY timestamp ASC`, since)
 	if err != nil {
 		return nil, err
@@ -240,17 +358,28 @@ func (s *StorageService) getCPUHistory(duration int) ([]map[string]interface{},
 	for rows.Next() {
 		var timestamp int64
 		var usage, load1, load5, load15 float64
+		var perCoreRaw sql.NullString
 
-		if err := rows.Scan(&timestamp, &usage, &load1, &load5, &load15); err != nil {
+		if err := rows.Scan(&timestamp, &usage, &load1, &load5, &load15, &perCoreRaw); err != nil {
 			continue
 		}
 
+		var perCore []float64
+		if perCoreRaw.Valid && perCoreRaw.String != "" {
+			// 无法解析的每核数据不影响总使用率，记录日志后跳过
+			if err := json.Unmarshal([]byte(perCoreRaw.String), &perCore); err != nil {
+				log.Printf("Failed to decode per-core CPU usage at %d: %v", timestamp, err)
+				perCore = nil
+			}
+		}
+
 		results = append(results, map[string]interface{}{
 			"timestamp":   time.Unix(timestamp, 0),
 			"usage":       usage,
 			"load1":       load1,
 			"load5":       load5,
 			"load15":      load15,
+			"per_core":    perCore,
 		})
 	}
 
@@ -369,9 +498,7 @@ func (s *StorageService) getNetworkHistory(duration int) ([]map[string]interface
 func (s *StorageService) CleanupOldData(retentionDays int) error {
 	cutoff := time.Now().AddDate(0, 0, -retentionDays).Unix()
 
-	tables := []string{"cpu_history", "memory_history", "disk_history", "network_history"}
-
-	for _, table := range tables {
+	for _, table := range rawHistoryTables {
 		if err := s.exec(fmt.Sprintf("DELETE FROM %s WHERE timestamp < ?", table), cutoff); err != nil {
 			return fmt.Errorf("failed to cleanup %s: %w", table, err)
 		}
@@ -380,8 +507,49 @@ func (s *StorageService) CleanupOldData(retentionDays int) error {
 	return nil
 }
 
+// startJob 启动后台定时任务，启动时立即执行一次。向返回的通道发送新的间隔会重置定时器
+func (s *StorageService) startJob(name string, interval time.Duration, fn func(now time.Time)) chan time.Duration {
+	reset := make(chan time.Duration, 1)
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		runJob(name, fn, time.Now())
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case interval := <-reset:
+				ticker.Reset(interval)
+			case now := <-ticker.C:
+				runJob(name, fn, now)
+			}
+		}
+	}()
+	return reset
+}
+
+// runJob 执行一次后台任务，panic 只影响本次执行，之后的定时执行不受影响
+func runJob(name string, fn func(now time.Time), now time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Storage job %s panic recovered: %v\n%s", name, r, debug.Stack())
+		}
+	}()
+	fn(now)
+}
+
 // Close 关闭数据库连接
 func (s *StorageService) Close() error {
+	// 等待后台任务退出后再关闭数据库
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.jobs.Wait()
+
 	if s.db != nil {
 		return s.db.Close()
 	}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+// WebhookPayload Webhook 请求体
+type WebhookPayload struct {
+	Event      string     `json:"event"`  // firing 或 resolved
+	Status     string     `json:"status"` // 告警状态
+	Host       string     `json:"host"`
+	AlertID    int64      `json:"alert_id"`
+	RuleID     int64      `json:"rule_id"`
+	RuleName   string     `json:"rule_name"`
+	Metric     string     `json:"metric"`
+	Target     string     `json:"target,omitempty"`
+	Level      string     `json:"level"`
+	Message    string     `json:"message"`
+	Value      float64    `json:"value"`
+	Threshold  float64    `json:"threshold"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	Timestamp  time.Time  `json:"timestamp"`
+
+	Alerts []models.Alert `json:"alerts,omitempty"` // 分组通知包含的所有告警
+}
+
+// WebhookChannel 通过 HTTP POST 发送 JSON 的通知渠道
+type WebhookChannel struct {
+	mu         sync.RWMutex
+	defaultURL string
+	client     *http.Client
+}
+
+// NewWebhookChannel 创建 Webhook 通知渠道，defaultURL 用于未配置目标的告警动作
+func NewWebhookChannel(defaultURL string, timeout time.Duration) *WebhookChannel {
+	return &WebhookChannel{
+		defaultURL: defaultURL,
+		client:     &http.Client{Timeout: timeout},
+	}
+}
+
+// SetURL 更新默认 Webhook URL，配置修改后立即生效
+func (wc *WebhookChannel) SetURL(defaultURL string) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	wc.defaultURL = defaultURL
+}
+
+// newWebhookPayload 根据通知构建请求体
+func newWebhookPayload(notification models.Notification) WebhookPayload {
+	alert := notification.Alert
+	return WebhookPayload{
+		Event:      notification.Event,
+		Status:     alert.Status,
+		Host:       notification.Host,
+		AlertID:    alert.ID,
+		RuleID:     alert.RuleID,
+		RuleName:   alert.RuleName,
+		Metric:     notification.Rule.Metric,
+		Target:     alert.Target,
+		Level:      alert.Level,
+		Message:    notification.Message,
+		Value:      alert.Value,
+		Threshold:  alert.Threshold,
+		CreatedAt:  alert.CreatedAt,
+		ResolvedAt: alert.ResolvedAt,
+		Timestamp:  notification.Timestamp,
+		Alerts:     notification.Alerts,
+	}
+}
+
+// Send 发送 Webhook 通知，非 2xx 响应视为失败
+func (wc *WebhookChannel) Send(target string, notification models.Notification) error {
+	url := target
+	if url == "" {
+		wc.mu.RLock()
+		url = wc.defaultURL
+		wc.mu.RUnlock()
+	}
+	if url == "" {
+		return fmt.Errorf("webhook URL not configured")
+	}
+
+	body, err := json.Marshal(newWebhookPayload(notification))
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "system-monitor")
+
+	resp, err := wc.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
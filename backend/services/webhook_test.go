@@ -0,0 +1,104 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"system-monitor/backend/models"
+)
+
+func testNotification(event string) models.Notification {
+	return models.Notification{
+		Event: event,
+		Alert: models.Alert{
+			ID:        1001,
+			RuleID:    3,
+			RuleName:  "Disk usage",
+			Target:    "/",
+			Message:   "Disk / is almost full",
+			Level:     "warning",
+			Value:     96,
+			Threshold: 95,
+			Status:    "active",
+			CreatedAt: time.Now(),
+		},
+		Rule:      models.AlertRule{ID: 3, Name: "Disk usage", Metric: "disk"},
+		Host:      "test-host",
+		Message:   "Disk / is almost full",
+		Timestamp: time.Now(),
+	}
+}
+
+func TestWebhookSendPayload(t *testing.T) {
+	var payload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("unexpected content type %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// 告警动作未配置目标时使用默认地址
+	wc := NewWebhookChannel(server.URL, 5*time.Second)
+	if err := wc.Send("", testNotification(notificationFiring)); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	if payload.Event != notificationFiring || payload.AlertID != 1001 || payload.RuleName != "Disk usage" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	if payload.Metric != "disk" || payload.Target != "/" || payload.Host != "test-host" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestWebhookSendErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	wc := NewWebhookChannel("", 5*time.Second)
+	if err := wc.Send("", testNotification(notificationFiring)); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Errorf("expected URL not configured error, got %v", err)
+	}
+
+	if err := wc.Send(server.URL, testNotification(notificationFiring)); err == nil || !strings.Contains(err.Error(), "status 502") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
+
+func TestWebhookSetURL(t *testing.T) {
+	received := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 配置更新后默认地址立即生效
+	wc := NewWebhookChannel("", 5*time.Second)
+	wc.SetURL(server.URL)
+	if err := wc.Send("", testNotification(notificationFiring)); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if received != 1 {
+		t.Errorf("expected 1 request to the updated URL, got %d", received)
+	}
+
+	wc.SetURL("")
+	if err := wc.Send("", testNotification(notificationFiring)); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Errorf("expected URL not configured error after clearing, got %v", err)
+	}
+}
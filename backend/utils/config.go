@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -26,6 +27,11 @@ type MonitoringConfig struct {
 	CPUAlertThreshold  float64 `yaml:"cpu_alert_threshold"`   // CPU告警阈值
 	MemoryAlertThreshold float64 `yaml:"memory_alert_threshold"` // 内存告警阈值
 	DiskAlertThreshold float64 `yaml:"disk_alert_threshold"`    // 磁盘告警阈值
+	RollupRetention1m  int `yaml:"rollup_retention_1m"`  // 1分钟汇总数据保留天数
+	RollupRetention15m int `yaml:"rollup_retention_15m"` // 15分钟汇总数据保留天数
+	RollupRetention1h  int `yaml:"rollup_retention_1h"`  // 1小时汇总数据保留天数
+	AlertRetention     int `yaml:"alert_retention"`      // 告警记录保留天数
+	CleanupInterval    int `yaml:"cleanup_interval"`     // 数据清理间隔（分钟）
 }
 
 // AlertsConfig 告警配置
@@ -39,6 +45,49 @@ type AlertsConfig struct {
 	EmailEnabled     bool     `yaml:"email_enabled"`     // 启用邮件通知
 	EmailRecipient   string   `yaml:"email_recipient"`   // 邮件接收者
 	WebhookURL       string   `yaml:"webhook_url"`       // Webhook URL
+	SMTP             SMTPConfig `yaml:"smtp"`            // 邮件服务器配置
+	Grouping         GroupingConfig `yaml:"grouping"`    // 告警通知分组配置
+	Remediation      RemediationConfig `yaml:"remediation"` // 告警修复动作白名单
+	Alertmanager     AlertmanagerConfig `yaml:"alertmanager"` // Alertmanager 推送配置
+}
+
+// AlertmanagerConfig 按 Alertmanager v2 API 推送告警的配置
+type AlertmanagerConfig struct {
+	URL            string `yaml:"url"`             // Alertmanager 地址，如 http://localhost:9093
+	GeneratorURL   string `yaml:"generator_url"`   // 告警的来源链接，为空时使用 system-monitor://主机名
+	ResendInterval int    `yaml:"resend_interval"` // 重新发送未解决告警的间隔（秒），超过4倍间隔未收到时 Alertmanager 自动解决告警
+}
+
+// RemediationConfig 告警修复动作白名单，告警动作只能按名称引用这里配置的命令和信号。
+// 只能在配置文件中修改，界面更新配置时保留原值
+type RemediationConfig struct {
+	Commands map[string]RemediationCommand `yaml:"commands"` // 允许执行的命令，键为告警动作引用的名称
+	Signals  []string                      `yaml:"signals"`  // 允许发送给进程的信号：HUP、INT、QUIT、KILL、TERM
+	Timeout  int                           `yaml:"timeout"`  // 命令默认超时时间（秒）
+}
+
+// RemediationCommand 允许执行的修复命令，不经过 shell 直接执行
+type RemediationCommand struct {
+	Path    string   `yaml:"path"`    // 可执行文件或脚本路径
+	Args    []string `yaml:"args"`    // 命令参数
+	Timeout int      `yaml:"timeout"` // 超时时间（秒），0 使用默认超时
+}
+
+// GroupingConfig 告警通知分组配置，等待时间内标签相同的告警合并为一条通知
+type GroupingConfig struct {
+	By   []string `yaml:"by"`   // 分组依据的标签：host、rule、metric、target、level
+	Wait int      `yaml:"wait"` // 分组等待时间（秒），0 表示不分组（默认），告警立即通知
+}
+
+// SMTPConfig 邮件服务器配置
+type SMTPConfig struct {
+	Host        string `yaml:"host"`         // SMTP 服务器地址
+	Port        int    `yaml:"port"`         // SMTP 服务器端口
+	Username    string `yaml:"username"`     // 登录用户名，为空时不认证
+	Password    string `yaml:"password"`     // 登录密码
+	From        string `yaml:"from"`         // 发件人地址，为空时使用用户名
+	StartTLS    bool   `yaml:"starttls"`     // 使用 STARTTLS 加密连接
+	BatchWindow int    `yaml:"batch_window"` // 合并发送的时间窗口（秒），0 表示不合并
 }
 
 // LoggingConfig 日志配置
@@ -85,6 +134,11 @@ func DefaultConfig() *Config {
 			CPUAlertThreshold:   80.0,
 			MemoryAlertThreshold: 90.0,
 			DiskAlertThreshold:  95.0,
+			RollupRetention1m:   14,
+			RollupRetention15m:  90,
+			RollupRetention1h:   365,
+			AlertRetention:      90,
+			CleanupInterval:     60,
 		},
 		Alerts: AlertsConfig{
 			CPUThreshold:      80.0,
@@ -96,6 +150,21 @@ func DefaultConfig() *Config {
 			EmailEnabled:      false,
 			EmailRecipient:    "",
 			WebhookURL:        "",
+			SMTP: SMTPConfig{
+				Port:        587,
+				StartTLS:    true,
+				BatchWindow: 30,
+			},
+			Grouping: GroupingConfig{
+				By:   []string{"host"},
+				Wait: 0,
+			},
+			Remediation: RemediationConfig{
+				Timeout: 30,
+			},
+			Alertmanager: AlertmanagerConfig{
+				ResendInterval: 60,
+			},
 		},
 		Logging: LoggingConfig{
 			Level:      "info",
@@ -193,6 +262,15 @@ func (c *Config) Validate() error {
 	if c.Monitoring.HistoryRetention <= 0 {
 		return fmt.Errorf("history retention must be positive")
 	}
+	if c.Monitoring.RollupRetention1m <= 0 || c.Monitoring.RollupRetention15m <= 0 || c.Monitoring.RollupRetention1h <= 0 {
+		return fmt.Errorf("rollup retention must be positive")
+	}
+	if c.Monitoring.AlertRetention <= 0 {
+		return fmt.Errorf("alert retention must be positive")
+	}
+	if c.Monitoring.CleanupInterval <= 0 {
+		return fmt.Errorf("cleanup interval must be positive")
+	}
 
 	// 验证告警配置
 	if c.Alerts.CPUThreshold <= 0 || c.Alerts.CPUThreshold > 100 {
@@ -204,6 +282,53 @@ func (c *Config) Validate() error {
 	if c.Alerts.DiskThreshold <= 0 || c.Alerts.DiskThreshold > 100 {
 		return fmt.Errorf("disk threshold must be between 0 and 100")
 	}
+	if c.Alerts.SMTP.Port < 0 || c.Alerts.SMTP.Port > 65535 {
+		return fmt.Errorf("SMTP port must be between 0 and 65535")
+	}
+	if c.Alerts.SMTP.BatchWindow < 0 {
+		return fmt.Errorf("email batch window cannot be negative")
+	}
+	if c.Alerts.Grouping.Wait < 0 {
+		return fmt.Errorf("grouping wait cannot be negative")
+	}
+	validGroupLabels := map[string]bool{
+		"host":   true,
+		"rule":   true,
+		"metric": true,
+		"target": true,
+		"level":  true,
+	}
+	for _, label := range c.Alerts.Grouping.By {
+		if !validGroupLabels[label] {
+			return fmt.Errorf("invalid grouping label: %s", label)
+		}
+	}
+	if c.Alerts.Alertmanager.ResendInterval < 0 {
+		return fmt.Errorf("alertmanager resend interval cannot be negative")
+	}
+	if c.Alerts.Remediation.Timeout < 0 {
+		return fmt.Errorf("remediation timeout cannot be negative")
+	}
+	for name, command := range c.Alerts.Remediation.Commands {
+		if command.Path == "" {
+			return fmt.Errorf("remediation command %s: path cannot be empty", name)
+		}
+		if command.Timeout < 0 {
+			return fmt.Errorf("remediation command %s: timeout cannot be negative", name)
+		}
+	}
+	validSignals := map[string]bool{
+		"HUP":  true,
+		"INT":  true,
+		"QUIT": true,
+		"KILL": true,
+		"TERM": true,
+	}
+	for _, signal := range c.Alerts.Remediation.Signals {
+		if !validSignals[signal] {
+			return fmt.Errorf("invalid remediation signal: %s", signal)
+		}
+	}
 
 	// 验证日志配置
 	validLogLevels := map[string]bool{
@@ -226,6 +351,14 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid theme: %s", c.UI.Theme)
 	}
 
+	validLanguages := map[string]bool{
+		"zh-CN": true,
+		"en-US": true,
+	}
+	if !validLanguages[c.UI.Language] {
+		return fmt.Errorf("invalid language: %s", c.UI.Language)
+	}
+
 	if c.UI.WindowWidth < 800 || c.UI.WindowWidth > 4000 {
 		return fmt.Errorf("window width must be between 800 and 4000")
 	}
@@ -244,6 +377,14 @@ func (c *Config) GetDatabasePath() string {
 	return c.Database.Path
 }
 
+// GetCleanupInterval 获取数据清理间隔
+func (c *Config) GetCleanupInterval() time.Duration {
+	if c.Monitoring.CleanupInterval <= 0 {
+		return time.Hour
+	}
+	return time.Duration(c.Monitoring.CleanupInterval) * time.Minute
+}
+
 // GetLogLevel 获取日志级别
 func (c *Config) GetLogLevel() string {
 	if c.Logging.Level == "" {
@@ -3,28 +3,60 @@
 import {models} from '../models';
 import {utils} from '../models';
 
+export function AcknowledgeAlert(arg1:number,arg2:string,arg3:string):Promise<void>;
+
+export function BacktestAlertRule(arg1:models.AlertRule,arg2:any,arg3:any):Promise<models.BacktestResult>;
+
 export function CreateAlertRule(arg1:models.AlertRule):Promise<void>;
 
+export function CreateMaintenanceWindow(arg1:models.MaintenanceWindow):Promise<models.MaintenanceWindow>;
+
 export function DeleteAlertRule(arg1:number):Promise<void>;
 
+export function DeleteMaintenanceWindow(arg1:number):Promise<void>;
+
+export function ExpireSilence(arg1:number):Promise<void>;
+
+export function ExportAlertRules(arg1:string):Promise<string>;
+
 export function GetAlertRules():Promise<Array<models.AlertRule>>;
 
 export function GetAlerts(arg1:number):Promise<Array<models.Alert>>;
 
 export function GetConfig():Promise<utils.Config>;
 
+export function GetDiskForecast(arg1:number):Promise<Array<models.DiskForecast>>;
+
 export function GetHardwareInfo():Promise<models.HardwareInfo>;
 
 export function GetHistoryData(arg1:string,arg2:number):Promise<any>;
 
+export function GetMaintenanceWindows():Promise<Array<models.MaintenanceWindow>>;
+
+export function GetNotificationDeliveries(arg1:number):Promise<Array<models.NotificationDelivery>>;
+
 export function GetProcesses(arg1:string,arg2:string,arg3:number):Promise<Array<models.ProcessInfo>>;
 
+export function GetRemediationExecutions(arg1:number):Promise<Array<models.RemediationExecution>>;
+
+export function GetSilences():Promise<Array<models.AlertSilence>>;
+
 export function GetSystemData():Promise<Record<string, any>>;
 
 export function GetSystemInfo():Promise<models.SystemInfo>;
 
+export function ImportAlertRules(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<models.RuleImportResult>;
+
 export function KillProcess(arg1:number):Promise<void>;
 
+export function QueryAlerts(arg1:models.AlertQuery):Promise<Array<models.Alert>>;
+
+export function SendTestEmail(arg1:string):Promise<void>;
+
+export function SilenceAlert(arg1:number,arg2:number):Promise<void>;
+
 export function UpdateAlertRule(arg1:models.AlertRule):Promise<void>;
 
 export function UpdateConfig(arg1:utils.Config):Promise<void>;
+
+export function UpdateMaintenanceWindow(arg1:models.MaintenanceWindow):Promise<void>;
@@ -2,14 +2,38 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcknowledgeAlert(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AcknowledgeAlert'](arg1, arg2, arg3);
+}
+
+export function BacktestAlertRule(arg1, arg2, arg3) {
+  return window['go']['main']['App']['BacktestAlertRule'](arg1, arg2, arg3);
+}
+
 export function CreateAlertRule(arg1) {
   return window['go']['main']['App']['CreateAlertRule'](arg1);
 }
 
+export function CreateMaintenanceWindow(arg1) {
+  return window['go']['main']['App']['CreateMaintenanceWindow'](arg1);
+}
+
 export function DeleteAlertRule(arg1) {
   return window['go']['main']['App']['DeleteAlertRule'](arg1);
 }
 
+export function DeleteMaintenanceWindow(arg1) {
+  return window['go']['main']['App']['DeleteMaintenanceWindow'](arg1);
+}
+
+export function ExpireSilence(arg1) {
+  return window['go']['main']['App']['ExpireSilence'](arg1);
+}
+
+export function ExportAlertRules(arg1) {
+  return window['go']['main']['App']['ExportAlertRules'](arg1);
+}
+
 export function GetAlertRules() {
   return window['go']['main']['App']['GetAlertRules']();
 }
@@ -22,6 +46,10 @@ export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
 
+export function GetDiskForecast(arg1) {
+  return window['go']['main']['App']['GetDiskForecast'](arg1);
+}
+
 export function GetHardwareInfo() {
   return window['go']['main']['App']['GetHardwareInfo']();
 }
@@ -30,10 +58,26 @@ export function GetHistoryData(arg1, arg2) {
   return window['go']['main']['App']['GetHistoryData'](arg1, arg2);
 }
 
+export function GetMaintenanceWindows() {
+  return window['go']['main']['App']['GetMaintenanceWindows']();
+}
+
+export function GetNotificationDeliveries(arg1) {
+  return window['go']['main']['App']['GetNotificationDeliveries'](arg1);
+}
+
 export function GetProcesses(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetProcesses'](arg1, arg2, arg3);
 }
 
+export function GetRemediationExecutions(arg1) {
+  return window['go']['main']['App']['GetRemediationExecutions'](arg1);
+}
+
+export function GetSilences() {
+  return window['go']['main']['App']['GetSilences']();
+}
+
 export function GetSystemData() {
   return window['go']['main']['App']['GetSystemData']();
 }
@@ -42,10 +86,26 @@ export function GetSystemInfo() {
   return window['go']['main']['App']['GetSystemInfo']();
 }
 
+export function ImportAlertRules(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ImportAlertRules'](arg1, arg2, arg3, arg4);
+}
+
 export function KillProcess(arg1) {
   return window['go']['main']['App']['KillProcess'](arg1);
 }
 
+export function QueryAlerts(arg1) {
+  return window['go']['main']['App']['QueryAlerts'](arg1);
+}
+
+export function SendTestEmail(arg1) {
+  return window['go']['main']['App']['SendTestEmail'](arg1);
+}
+
+export function SilenceAlert(arg1, arg2) {
+  return window['go']['main']['App']['SilenceAlert'](arg1, arg2);
+}
+
 export function UpdateAlertRule(arg1) {
   return window['go']['main']['App']['UpdateAlertRule'](arg1);
 }
@@ -53,3 +113,7 @@ export function UpdateAlertRule(arg1) {
 export function UpdateConfig(arg1) {
   return window['go']['main']['App']['UpdateConfig'](arg1);
 }
+
+export function UpdateMaintenanceWindow(arg1) {
+  return window['go']['main']['App']['UpdateMaintenanceWindow'](arg1);
+}
@@ -4,6 +4,7 @@ export namespace models {
 	    id: number;
 	    rule_id: number;
 	    rule_name: string;
+	    target?: string;
 	    message: string;
 	    level: string;
 	    value: number;
@@ -13,6 +14,20 @@ export namespace models {
 	    created_at: any;
 	    // Go type: time
 	    resolved_at?: any;
+	    // Go type: time
+	    pending_since?: any;
+	    acknowledged_by?: string;
+	    // Go type: time
+	    acknowledged_at?: any;
+	    ack_comment?: string;
+	    silenced: boolean;
+	    flapping: boolean;
+	    suppressed: boolean;
+	    inhibited: boolean;
+	    remediated: boolean;
+	    escalation?: number;
+	    // Go type: time
+	    last_notified_at?: any;
 	
 	    static createFrom(source: any = {}) {
 	        return new Alert(source);
@@ -23,6 +38,7 @@ export namespace models {
 	        this.id = source["id"];
 	        this.rule_id = source["rule_id"];
 	        this.rule_name = source["rule_name"];
+	        this.target = source["target"];
 	        this.message = source["message"];
 	        this.level = source["level"];
 	        this.value = source["value"];
@@ -30,6 +46,17 @@ export namespace models {
 	        this.status = source["status"];
 	        this.created_at = this.convertValues(source["created_at"], null);
 	        this.resolved_at = this.convertValues(source["resolved_at"], null);
+	        this.pending_since = this.convertValues(source["pending_since"], null);
+	        this.acknowledged_by = source["acknowledged_by"];
+	        this.acknowledged_at = this.convertValues(source["acknowledged_at"], null);
+	        this.ack_comment = source["ack_comment"];
+	        this.silenced = source["silenced"];
+	        this.flapping = source["flapping"];
+	        this.suppressed = source["suppressed"];
+	        this.inhibited = source["inhibited"];
+	        this.remediated = source["remediated"];
+	        this.escalation = source["escalation"];
+	        this.last_notified_at = this.convertValues(source["last_notified_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -54,6 +81,7 @@ export namespace models {
 	    type: string;
 	    target: string;
 	    level: string;
+	    template?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new AlertAction(source);
@@ -64,14 +92,133 @@ export namespace models {
 	        this.type = source["type"];
 	        this.target = source["target"];
 	        this.level = source["level"];
+	        this.template = source["template"];
+	    }
+	}
+	export class AlertQuery {
+	    limit: number;
+	    level?: string;
+	    rule_id?: number;
+	    status?: string;
+	    // Go type: time
+	    since?: any;
+	    // Go type: time
+	    until?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new AlertQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.limit = source["limit"];
+	        this.level = source["level"];
+	        this.rule_id = source["rule_id"];
+	        this.status = source["status"];
+	        this.since = this.convertValues(source["since"], null);
+	        this.until = this.convertValues(source["until"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EscalationStep {
+	    after: number;
+	    level?: string;
+	    actions?: AlertAction[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EscalationStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.after = source["after"];
+	        this.level = source["level"];
+	        this.actions = this.convertValues(source["actions"], AlertAction);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EscalationPolicy {
+	    repeat_interval: number;
+	    steps: EscalationStep[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EscalationPolicy(source);
 	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repeat_interval = source["repeat_interval"];
+	        this.steps = this.convertValues(source["steps"], EscalationStep);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class AlertRule {
 	    id: number;
 	    name: string;
 	    metric: string;
+	    target: string;
 	    operator: string;
 	    threshold: number;
+	    clear_threshold?: number;
+	    expression?: string;
+	    type?: string;
+	    anomaly?: AnomalyConfig;
+	    process?: ProcessConfig;
+	    flap?: FlapConfig;
+	    escalation?: EscalationPolicy;
+	    inhibits?: string[];
+	    message_template?: string;
 	    duration: number;
 	    enabled: boolean;
 	    actions: AlertAction[];
@@ -89,8 +236,18 @@ export namespace models {
 	        this.id = source["id"];
 	        this.name = source["name"];
 	        this.metric = source["metric"];
+	        this.target = source["target"];
 	        this.operator = source["operator"];
 	        this.threshold = source["threshold"];
+	        this.clear_threshold = source["clear_threshold"];
+	        this.expression = source["expression"];
+	        this.type = source["type"];
+	        this.anomaly = this.convertValues(source["anomaly"], AnomalyConfig);
+	        this.process = this.convertValues(source["process"], ProcessConfig);
+	        this.flap = this.convertValues(source["flap"], FlapConfig);
+	        this.escalation = this.convertValues(source["escalation"], EscalationPolicy);
+	        this.inhibits = source["inhibits"];
+	        this.message_template = source["message_template"];
 	        this.duration = source["duration"];
 	        this.enabled = source["enabled"];
 	        this.actions = this.convertValues(source["actions"], AlertAction);
@@ -116,6 +273,155 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class AlertSilence {
+	    id: number;
+	    rule_id: number;
+	    target?: string;
+	    alert_id?: number;
+	    created_by: string;
+	    comment: string;
+	    // Go type: time
+	    starts_at: any;
+	    // Go type: time
+	    ends_at: any;
+	    expired: boolean;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new AlertSilence(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.rule_id = source["rule_id"];
+	        this.target = source["target"];
+	        this.alert_id = source["alert_id"];
+	        this.created_by = source["created_by"];
+	        this.comment = source["comment"];
+	        this.starts_at = this.convertValues(source["starts_at"], null);
+	        this.ends_at = this.convertValues(source["ends_at"], null);
+	        this.expired = source["expired"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AnomalyConfig {
+	    sigma: number;
+	    window: number;
+	    seasonal: boolean;
+	    min_samples: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnomalyConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sigma = source["sigma"];
+	        this.window = source["window"];
+	        this.seasonal = source["seasonal"];
+	        this.min_samples = source["min_samples"];
+	    }
+	}
+	export class BacktestFiring {
+	    target?: string;
+	    // Go type: time
+	    starts_at: any;
+	    // Go type: time
+	    ends_at?: any;
+	    duration: number;
+	    value: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BacktestFiring(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = source["target"];
+	        this.starts_at = this.convertValues(source["starts_at"], null);
+	        this.ends_at = this.convertValues(source["ends_at"], null);
+	        this.duration = source["duration"];
+	        this.value = source["value"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BacktestResult {
+	    // Go type: time
+	    since: any;
+	    // Go type: time
+	    until: any;
+	    samples: number;
+	    firings: BacktestFiring[];
+	    firing_time: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BacktestResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.since = this.convertValues(source["since"], null);
+	        this.until = this.convertValues(source["until"], null);
+	        this.samples = source["samples"];
+	        this.firings = this.convertValues(source["firings"], BacktestFiring);
+	        this.firing_time = source["firing_time"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class BatteryInfo {
 	    present: boolean;
 	    percentage?: number;
@@ -156,6 +462,58 @@ export namespace models {
 	        this.details = source["details"];
 	    }
 	}
+	export class DiskForecast {
+	    mountpoint: string;
+	    device: string;
+	    used: number;
+	    free: number;
+	    used_percent: number;
+	    fill_rate: number;
+	    hours_to_full?: number;
+	    // Go type: time
+	    predicted_full_at?: any;
+	    samples: number;
+	    window: number;
+	    // Go type: time
+	    timestamp: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiskForecast(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mountpoint = source["mountpoint"];
+	        this.device = source["device"];
+	        this.used = source["used"];
+	        this.free = source["free"];
+	        this.used_percent = source["used_percent"];
+	        this.fill_rate = source["fill_rate"];
+	        this.hours_to_full = source["hours_to_full"];
+	        this.predicted_full_at = this.convertValues(source["predicted_full_at"], null);
+	        this.samples = source["samples"];
+	        this.window = source["window"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class DiskModel {
 	    vendor: string;
 	    model: string;
@@ -194,6 +552,70 @@ export namespace models {
 	        this.refresh_rate = source["refresh_rate"];
 	    }
 	}
+	export class FlapConfig {
+	    changes: number;
+	    window: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FlapConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.changes = source["changes"];
+	        this.window = source["window"];
+	    }
+	}
+	export class MaintenanceWindow {
+	    id: number;
+	    name: string;
+	    schedule: string;
+	    duration: number;
+	    timezone: string;
+	    rule_ids: number[];
+	    enabled: boolean;
+	    comment: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaintenanceWindow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.schedule = source["schedule"];
+	        this.duration = source["duration"];
+	        this.timezone = source["timezone"];
+	        this.rule_ids = source["rule_ids"];
+	        this.enabled = source["enabled"];
+	        this.comment = source["comment"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class MemoryModule {
 	    vendor: string;
 	    model: string;
@@ -301,6 +723,73 @@ export namespace models {
 	}
 	
 	
+	export class NotificationDelivery {
+	    id: number;
+	    alert_id: number;
+	    rule_id: number;
+	    channel: string;
+	    target: string;
+	    event: string;
+	    status: string;
+	    attempts: number;
+	    error?: string;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationDelivery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.alert_id = source["alert_id"];
+	        this.rule_id = source["rule_id"];
+	        this.channel = source["channel"];
+	        this.target = source["target"];
+	        this.event = source["event"];
+	        this.status = source["status"];
+	        this.attempts = source["attempts"];
+	        this.error = source["error"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProcessConfig {
+	    name?: string;
+	    cmdline?: string;
+	    user?: string;
+	    condition: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProcessConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.cmdline = source["cmdline"];
+	        this.user = source["user"];
+	        this.condition = source["condition"];
+	    }
+	}
 	export class ProcessTimes {
 	    user: number;
 	    system: number;
@@ -397,6 +886,111 @@ export namespace models {
 		}
 	}
 	
+	export class RemediationExecution {
+	    id: number;
+	    alert_id: number;
+	    rule_id: number;
+	    action: string;
+	    target: string;
+	    pid?: number;
+	    status: string;
+	    exit_code: number;
+	    output?: string;
+	    error?: string;
+	    duration: number;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new RemediationExecution(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.alert_id = source["alert_id"];
+	        this.rule_id = source["rule_id"];
+	        this.action = source["action"];
+	        this.target = source["target"];
+	        this.pid = source["pid"];
+	        this.status = source["status"];
+	        this.exit_code = source["exit_code"];
+	        this.output = source["output"];
+	        this.error = source["error"];
+	        this.duration = source["duration"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RuleChange {
+	    name: string;
+	    fields: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RuleChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.fields = source["fields"];
+	    }
+	}
+	export class RuleImportResult {
+	    created: string[];
+	    updated: RuleChange[];
+	    deleted: string[];
+	    unchanged: string[];
+	    dry_run: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RuleImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.created = source["created"];
+	        this.updated = this.convertValues(source["updated"], RuleChange);
+	        this.deleted = source["deleted"];
+	        this.unchanged = source["unchanged"];
+	        this.dry_run = source["dry_run"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class SystemInfo {
 	    hostname: string;
 	    os: string;
@@ -456,6 +1050,110 @@ export namespace models {
 
 export namespace utils {
 	
+	export class GroupingConfig {
+	    By: string[];
+	    Wait: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupingConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.By = source["By"];
+	        this.Wait = source["Wait"];
+	    }
+	}
+	export class SMTPConfig {
+	    Host: string;
+	    Port: number;
+	    Username: string;
+	    Password: string;
+	    From: string;
+	    StartTLS: boolean;
+	    BatchWindow: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SMTPConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Host = source["Host"];
+	        this.Port = source["Port"];
+	        this.Username = source["Username"];
+	        this.Password = source["Password"];
+	        this.From = source["From"];
+	        this.StartTLS = source["StartTLS"];
+	        this.BatchWindow = source["BatchWindow"];
+	    }
+	}
+	export class AlertmanagerConfig {
+	    URL: string;
+	    GeneratorURL: string;
+	    ResendInterval: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AlertmanagerConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.URL = source["URL"];
+	        this.GeneratorURL = source["GeneratorURL"];
+	        this.ResendInterval = source["ResendInterval"];
+	    }
+	}
+	export class RemediationCommand {
+	    Path: string;
+	    Args: string[];
+	    Timeout: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RemediationCommand(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Path = source["Path"];
+	        this.Args = source["Args"];
+	        this.Timeout = source["Timeout"];
+	    }
+	}
+	export class RemediationConfig {
+	    Commands: {[key: string]: RemediationCommand};
+	    Signals: string[];
+	    Timeout: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RemediationConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Commands = this.convertValues(source["Commands"], RemediationCommand, true);
+	        this.Signals = source["Signals"];
+	        this.Timeout = source["Timeout"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class AlertsConfig {
 	    CPUThreshold: number;
 	    MemoryThreshold: number;
@@ -466,6 +1164,10 @@ export namespace utils {
 	    EmailEnabled: boolean;
 	    EmailRecipient: string;
 	    WebhookURL: string;
+	    SMTP: SMTPConfig;
+	    Grouping: GroupingConfig;
+	    Remediation: RemediationConfig;
+	    Alertmanager: AlertmanagerConfig;
 	
 	    static createFrom(source: any = {}) {
 	        return new AlertsConfig(source);
@@ -482,7 +1184,29 @@ export namespace utils {
 	        this.EmailEnabled = source["EmailEnabled"];
 	        this.EmailRecipient = source["EmailRecipient"];
 	        this.WebhookURL = source["WebhookURL"];
+	        this.SMTP = this.convertValues(source["SMTP"], SMTPConfig);
+	        this.Grouping = this.convertValues(source["Grouping"], GroupingConfig);
+	        this.Remediation = this.convertValues(source["Remediation"], RemediationConfig);
+	        this.Alertmanager = this.convertValues(source["Alertmanager"], AlertmanagerConfig);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class UIConfig {
 	    Theme: string;
@@ -564,6 +1288,11 @@ export namespace utils {
 	    CPUAlertThreshold: number;
 	    MemoryAlertThreshold: number;
 	    DiskAlertThreshold: number;
+	    RollupRetention1m: number;
+	    RollupRetention15m: number;
+	    RollupRetention1h: number;
+	    AlertRetention: number;
+	    CleanupInterval: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new MonitoringConfig(source);
@@ -578,6 +1307,11 @@ export namespace utils {
 	        this.CPUAlertThreshold = source["CPUAlertThreshold"];
 	        this.MemoryAlertThreshold = source["MemoryAlertThreshold"];
 	        this.DiskAlertThreshold = source["DiskAlertThreshold"];
+	        this.RollupRetention1m = source["RollupRetention1m"];
+	        this.RollupRetention15m = source["RollupRetention15m"];
+	        this.RollupRetention1h = source["RollupRetention1h"];
+	        this.AlertRetention = source["AlertRetention"];
+	        this.CleanupInterval = source["CleanupInterval"];
 	    }
 	}
 	export class Config {
@@ -23,13 +23,18 @@ var assets embed.FS
 
 // App 应用程序结构体
 type App struct {
-	ctx              context.Context
-	config           *utils.Config
-	logger           *utils.Logger
-	monitorService   *services.MonitorService
-	storageService   *services.StorageService
-	alertingService  *services.AlertingService
-	eventManager     *services.EventManager
+	ctx                 context.Context
+	config              *utils.Config
+	logger              *utils.Logger
+	monitorService      *services.MonitorService
+	storageService      *services.StorageService
+	alertingService     *services.AlertingService
+	notificationService *services.NotificationService
+	webhookChannel      *services.WebhookChannel
+	emailChannel        *services.EmailChannel
+	remediationService  *services.RemediationService
+	alertmanagerChannel *services.AlertmanagerChannel
+	eventManager        *services.EventManager
 }
 
 // NewApp 创建新的应用程序实例
@@ -169,16 +174,51 @@ func (a *App) initializeServicesSafe(ctx context.Context) error {
 	} else {
 		log.Println("✅ 存储服务初始化成功")
 		a.storageService = storageService
+
+		// 启动历史数据汇总任务
+		storageService.StartRollups(time.Minute)
+
+		// 启动历史数据清理任务
+		storageService.StartRetention(a.retentionPolicy(), a.config.GetCleanupInterval(), a.eventManager)
 	}
 
 	// 初始化告警服务
 	a.alertingService = services.NewAlertingService(a.config, a.eventManager)
+	a.alertingService.SetLanguage(a.config.UI.Language)
+	a.alertingService.SetGrouping(a.config.Alerts.Grouping.By, time.Duration(a.config.Alerts.Grouping.Wait)*time.Second)
+	if storageService != nil {
+		a.alertingService.SetStorageService(storageService)
+		if err := a.alertingService.LoadRules(); err != nil {
+			log.Printf("⚠️ 加载告警规则失败: %v", err)
+		}
+	}
 	if err := a.alertingService.CreateDefaultRules(); err != nil {
 		log.Printf("⚠️ 创建默认告警规则失败: %v", err)
 	} else {
 		log.Println("✅ 告警规则创建成功")
 	}
 
+	// 初始化通知服务
+	a.notificationService = services.NewNotificationService(a.eventManager)
+	a.notificationService.SetLanguage(a.config.UI.Language)
+	if storageService != nil {
+		a.notificationService.SetStorageService(storageService)
+	}
+	a.webhookChannel = services.NewWebhookChannel(a.config.Alerts.WebhookURL, 10*time.Second)
+	a.notificationService.RegisterChannel("webhook", a.webhookChannel)
+	a.emailChannel = services.NewEmailChannel(a.config.Alerts)
+	a.notificationService.RegisterChannel("email", a.emailChannel)
+	a.alertmanagerChannel = services.NewAlertmanagerChannel(a.config.Alerts.Alertmanager, 10*time.Second)
+	a.notificationService.RegisterChannel("alertmanager", a.alertmanagerChannel)
+	a.alertingService.SetNotificationService(a.notificationService)
+
+	// 初始化修复动作服务
+	a.remediationService = services.NewRemediationService(a.config.Alerts.Remediation, a.eventManager)
+	if storageService != nil {
+		a.remediationService.SetStorageService(storageService)
+	}
+	a.alertingService.SetRemediationService(a.remediationService)
+
 	// 初始化监控服务
 	a.monitorService = services.NewMonitorService(ctx, a.config, a.eventManager, a.alertingService)
 	if storageService != nil {
@@ -190,6 +230,22 @@ func (a *App) initializeServicesSafe(ctx context.Context) error {
 	return nil
 }
 
+// retentionPolicy 根据配置生成数据保留策略
+func (a *App) retentionPolicy() services.RetentionPolicy {
+	day := 24 * time.Hour
+	m := a.config.Monitoring
+
+	return services.RetentionPolicy{
+		Raw: time.Duration(m.HistoryRetention) * day,
+		Rollups: map[string]time.Duration{
+			"1m":  time.Duration(m.RollupRetention1m) * day,
+			"15m": time.Duration(m.RollupRetention15m) * day,
+			"1h":  time.Duration(m.RollupRetention1h) * day,
+		},
+		Alerts: time.Duration(m.AlertRetention) * day,
+	}
+}
+
 // OnDomReady DOM加载完成时的回调函数
 func (a *App) OnDomReady(ctx context.Context) {
 	// 添加 panic 恢复
@@ -287,6 +343,19 @@ func (a *App) OnShutdown(ctx context.Context) {
 		a.monitorService.Stop()
 	}
 
+	if a.alertmanagerChannel != nil {
+		a.alertmanagerChannel.Stop()
+	}
+
+	// 等待正在发送的通知和正在执行的修复动作完成，它们会记录结果到数据库
+	if a.notificationService != nil {
+		a.notificationService.Wait()
+	}
+
+	if a.remediationService != nil {
+		a.remediationService.Wait()
+	}
+
 	if a.storageService != nil {
 		a.storageService.Close()
 	}
@@ -338,6 +407,14 @@ func (a *App) GetHistoryData(metric string, duration int) (interface{}, error) {
 	return a.storageService.GetHistoryData(metric, duration)
 }
 
+// GetDiskForecast 获取各挂载点的写满时间预测，hours 为拟合使用的历史小时数，0 表示默认值，最多30天
+func (a *App) GetDiskForecast(hours int) ([]models.DiskForecast, error) {
+	if a.storageService == nil {
+		return nil, fmt.Errorf("storage service not initialized")
+	}
+	return a.storageService.ForecastDisks(services.ForecastWindow(hours), time.Now())
+}
+
 // GetProcesses 获取进程列表
 func (a *App) GetProcesses(sortBy string, order string, limit int) ([]models.ProcessInfo, error) {
 	if a.monitorService == nil {
@@ -394,6 +471,39 @@ func (a *App) DeleteAlertRule(id int64) error {
 	return a.alertingService.DeleteRule(id)
 }
 
+// ExportAlertRules 将所有告警规则导出为规则包，format 为 yaml（默认）或 json
+func (a *App) ExportAlertRules(format string) (string, error) {
+	if a.alertingService == nil {
+		return "", fmt.Errorf("alerting service not initialized")
+	}
+
+	data, err := a.alertingService.ExportRules(format)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ImportAlertRules 导入规则包，mode 为 merge（默认）或 replace，dryRun 为 true 时只预览变更
+func (a *App) ImportAlertRules(data string, format string, mode string, dryRun bool) (models.RuleImportResult, error) {
+	if a.alertingService == nil {
+		return models.RuleImportResult{}, fmt.Errorf("alerting service not initialized")
+	}
+
+	if !dryRun {
+		a.logger.Info("Importing alert rules (mode: %s)", mode)
+	}
+	return a.alertingService.ImportRules([]byte(data), format, mode, dryRun)
+}
+
+// BacktestAlertRule 在历史数据上回放候选规则，返回规则在时间范围内会产生的触发
+func (a *App) BacktestAlertRule(rule models.AlertRule, since time.Time, until time.Time) (models.BacktestResult, error) {
+	if a.alertingService == nil {
+		return models.BacktestResult{}, fmt.Errorf("alerting service not initialized")
+	}
+	return a.alertingService.BacktestRule(rule, since, until)
+}
+
 // GetAlerts 获取告警列表
 func (a *App) GetAlerts(limit int) ([]models.Alert, error) {
 	if a.alertingService == nil {
@@ -402,6 +512,122 @@ func (a *App) GetAlerts(limit int) ([]models.Alert, error) {
 	return a.alertingService.GetAlerts(limit)
 }
 
+// QueryAlerts 按级别、规则和时间范围查询告警历史
+func (a *App) QueryAlerts(query models.AlertQuery) ([]models.Alert, error) {
+	if a.alertingService == nil {
+		return nil, fmt.Errorf("alerting service not initialized")
+	}
+	return a.alertingService.QueryAlerts(query)
+}
+
+// AcknowledgeAlert 确认告警，未指定确认人时使用当前系统用户
+func (a *App) AcknowledgeAlert(alertID int64, by string, comment string) error {
+	if a.alertingService == nil {
+		return fmt.Errorf("alerting service not initialized")
+	}
+
+	if by == "" {
+		if currentUser, err := utils.GetCurrentUser(); err == nil {
+			by = currentUser.Username
+		}
+	}
+
+	a.logger.Info("Acknowledging alert %d by %s", alertID, by)
+	return a.alertingService.AcknowledgeAlert(alertID, by, comment)
+}
+
+// SilenceAlert 静默告警指定的分钟数
+func (a *App) SilenceAlert(alertID int64, minutes int) error {
+	if a.alertingService == nil {
+		return fmt.Errorf("alerting service not initialized")
+	}
+
+	a.logger.Info("Silencing alert %d for %d minutes", alertID, minutes)
+	return a.alertingService.SilenceAlert(alertID, time.Duration(minutes)*time.Minute)
+}
+
+// GetSilences 获取未过期的告警静默
+func (a *App) GetSilences() ([]models.AlertSilence, error) {
+	if a.alertingService == nil {
+		return nil, fmt.Errorf("alerting service not initialized")
+	}
+	return a.alertingService.GetSilences(), nil
+}
+
+// ExpireSilence 提前结束告警静默
+func (a *App) ExpireSilence(id int64) error {
+	if a.alertingService == nil {
+		return fmt.Errorf("alerting service not initialized")
+	}
+
+	a.logger.Info("Expiring silence %d", id)
+	return a.alertingService.ExpireSilence(id)
+}
+
+// GetMaintenanceWindows 获取所有维护窗口
+func (a *App) GetMaintenanceWindows() ([]models.MaintenanceWindow, error) {
+	if a.alertingService == nil {
+		return nil, fmt.Errorf("alerting service not initialized")
+	}
+	return a.alertingService.GetMaintenanceWindows(), nil
+}
+
+// CreateMaintenanceWindow 创建维护窗口，窗口内触发的告警只记录不通知
+func (a *App) CreateMaintenanceWindow(window models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	if a.alertingService == nil {
+		return models.MaintenanceWindow{}, fmt.Errorf("alerting service not initialized")
+	}
+
+	a.logger.Info("Creating maintenance window: %s", window.Name)
+	return a.alertingService.CreateMaintenanceWindow(window)
+}
+
+// UpdateMaintenanceWindow 更新维护窗口
+func (a *App) UpdateMaintenanceWindow(window models.MaintenanceWindow) error {
+	if a.alertingService == nil {
+		return fmt.Errorf("alerting service not initialized")
+	}
+
+	a.logger.Info("Updating maintenance window: %s", window.Name)
+	return a.alertingService.UpdateMaintenanceWindow(window)
+}
+
+// DeleteMaintenanceWindow 删除维护窗口
+func (a *App) DeleteMaintenanceWindow(id int64) error {
+	if a.alertingService == nil {
+		return fmt.Errorf("alerting service not initialized")
+	}
+
+	a.logger.Info("Deleting maintenance window: %d", id)
+	return a.alertingService.DeleteMaintenanceWindow(id)
+}
+
+// GetNotificationDeliveries 获取最近的告警通知投递记录
+func (a *App) GetNotificationDeliveries(limit int) ([]models.NotificationDelivery, error) {
+	if a.notificationService == nil {
+		return nil, fmt.Errorf("notification service not initialized")
+	}
+	return a.notificationService.GetDeliveries(limit)
+}
+
+// GetRemediationExecutions 获取最近的告警修复动作执行记录
+func (a *App) GetRemediationExecutions(limit int) ([]models.RemediationExecution, error) {
+	if a.remediationService == nil {
+		return nil, fmt.Errorf("remediation service not initialized")
+	}
+	return a.remediationService.GetExecutions(limit)
+}
+
+// SendTestEmail 按当前邮件配置发送测试邮件，recipient 为空时使用配置的收件人
+func (a *App) SendTestEmail(recipient string) error {
+	if a.emailChannel == nil {
+		return fmt.Errorf("notification service not initialized")
+	}
+
+	a.logger.Info("Sending test email to %s", recipient)
+	return a.emailChannel.SendTest(recipient, a.config.UI.Language)
+}
+
 // GetConfig 获取配置
 func (a *App) GetConfig() (*utils.Config, error) {
 	return a.config, nil
@@ -409,7 +635,28 @@ func (a *App) GetConfig() (*utils.Config, error) {
 
 // UpdateConfig 更新配置
 func (a *App) UpdateConfig(config utils.Config) error {
+	// 修复动作白名单只能在配置文件中修改，避免从界面添加任意命令
+	config.Alerts.Remediation = a.config.Alerts.Remediation
 	a.config = &config
+	if a.alertingService != nil {
+		a.alertingService.SetLanguage(config.UI.Language)
+		a.alertingService.SetGrouping(config.Alerts.Grouping.By, time.Duration(config.Alerts.Grouping.Wait)*time.Second)
+	}
+	if a.notificationService != nil {
+		a.notificationService.SetLanguage(config.UI.Language)
+	}
+	if a.webhookChannel != nil {
+		a.webhookChannel.SetURL(config.Alerts.WebhookURL)
+	}
+	if a.emailChannel != nil {
+		a.emailChannel.SetConfig(config.Alerts)
+	}
+	if a.alertmanagerChannel != nil {
+		a.alertmanagerChannel.SetConfig(config.Alerts.Alertmanager)
+	}
+	if a.storageService != nil {
+		a.storageService.SetRetention(a.retentionPolicy(), config.GetCleanupInterval())
+	}
 	a.logger.Info("Configuration updated")
 	return a.config.Save()
 }